@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"dpbug/fishpi/go-client/pkg/fishpi"
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+)
+
+func runArticleCommand(client *fishpi.Client, args []string) {
+	if len(args) == 0 || args[0] != "post" {
+		printCommandUsage()
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("article post", flag.ExitOnError)
+	file := fs.String("file", "", "Markdown 文件路径（支持 front matter）")
+	tags := fs.String("tags", "", "标签，逗号分隔（覆盖 front matter）")
+	title := fs.String("title", "", "标题（覆盖 front matter）")
+	update := fs.String("update", "", "要更新的帖子 ID（不填则发布新帖）")
+	fs.Parse(args[1:])
+
+	if *file == "" {
+		fmt.Println("⚠ 请通过 --file 指定 Markdown 文件")
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		fmt.Printf("⚠ 读取文件失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	draft, articleID, err := parseArticleFile(string(data))
+	if err != nil {
+		fmt.Printf("⚠ 解析文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	if *tags != "" {
+		draft.Tags = splitTags(*tags)
+	}
+	if *title != "" {
+		draft.Title = *title
+	}
+	if *update != "" {
+		articleID = *update
+	}
+
+	loginUser(client)
+
+	if articleID != "" {
+		fmt.Printf("\n正在更新帖子: %s\n", draft.Title)
+		if err := client.UpdateArticle(articleID, draft); err != nil {
+			fmt.Printf("⚠ 更新失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ 更新成功！%s/article/%s\n", client.BaseURL, articleID)
+		return
+	}
+
+	fmt.Printf("\n正在发布帖子: %s\n", draft.Title)
+	id, err := client.PostArticle(draft)
+	if err != nil {
+		fmt.Printf("⚠ 发布失败: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ 发布成功！%s/article/%s\n", client.BaseURL, id)
+}
+
+// parseArticleFile 解析带 front matter 的 Markdown 文件
+// front matter 位于文件开头的两行 --- 之间，每行一个 key: value，支持的键：
+// title, tags, type, reward_point, reward_content, qna_offer_point,
+// anonymous, commentable, notify_followers, hidden, id（填写则更新该帖子）
+// tags 可写为 "a, b"、"[a, b]" 或在 tags: 下逐行写 "- a"。
+// 未指定标题时使用正文中的第一个一级标题。
+func parseArticleFile(text string) (*models.ArticleDraft, string, error) {
+	draft := models.NewArticleDraft("", "")
+	var articleID string
+
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	body := text
+	lines := strings.Split(text, "\n")
+	if strings.TrimSpace(lines[0]) == "---" {
+		end := -1
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return nil, "", fmt.Errorf("front matter 缺少结束标记 ---")
+		}
+		body = strings.Join(lines[end+1:], "\n")
+
+		listKey := "" // 正在读取的列表字段（值写在后续的 "- " 行中）
+		for _, line := range lines[1:end] {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			if item, ok := strings.CutPrefix(line, "-"); ok {
+				if listKey != "tags" {
+					return nil, "", fmt.Errorf("front matter 列表项不属于 tags 字段: %s", line)
+				}
+				if tag := strings.Trim(strings.TrimSpace(item), `"'`); tag != "" {
+					draft.Tags = append(draft.Tags, tag)
+				}
+				continue
+			}
+			listKey = ""
+
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return nil, "", fmt.Errorf("front matter 格式错误: %s", line)
+			}
+			key = strings.ToLower(strings.TrimSpace(key))
+			value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+			var err error
+			switch key {
+			case "title":
+				draft.Title = value
+			case "tags":
+				if value == "" {
+					listKey = key
+					draft.Tags = nil
+					continue
+				}
+				if strings.HasPrefix(value, "[") != strings.HasSuffix(value, "]") {
+					return nil, "", fmt.Errorf("front matter 字段 tags 的值无效: %s", value)
+				}
+				draft.Tags = splitTags(value)
+			case "type":
+				draft.Type, err = strconv.Atoi(value)
+			case "reward_point":
+				draft.RewardPoint, err = strconv.Atoi(value)
+			case "reward_content":
+				draft.RewardContent = value
+			case "qna_offer_point":
+				draft.QnAOfferPoint, err = strconv.Atoi(value)
+			case "anonymous":
+				draft.Anonymous, err = strconv.ParseBool(value)
+			case "commentable":
+				draft.Commentable, err = strconv.ParseBool(value)
+			case "notify_followers":
+				draft.NotifyFollowers, err = strconv.ParseBool(value)
+			case "hidden":
+				draft.Hidden, err = strconv.ParseBool(value)
+			case "id":
+				articleID = value
+			default:
+				return nil, "", fmt.Errorf("front matter 不支持的字段: %s", key)
+			}
+			if err != nil {
+				return nil, "", fmt.Errorf("front matter 字段 %s 的值无效: %s", key, value)
+			}
+		}
+	}
+
+	if draft.Title == "" {
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			if strings.HasPrefix(line, "# ") {
+				draft.Title = strings.TrimSpace(line[2:])
+				body = strings.Join(append(lines[:i:i], lines[i+1:]...), "\n")
+				break
+			}
+		}
+	}
+
+	draft.Content = strings.TrimSpace(body)
+	return draft, articleID, nil
+}
+
+// splitTags 解析 "a,b" 或 "[a, b]" 格式的标签列表
+func splitTags(value string) []string {
+	value = strings.Trim(strings.TrimSpace(value), "[]")
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.Trim(strings.TrimSpace(tag), `"'`); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseArticleFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		title   string
+		tags    []string
+		content string
+		id      string
+		wantErr string
+	}{
+		{
+			name:    "无 front matter 使用一级标题",
+			text:    "# 标题\n\n正文",
+			title:   "标题",
+			content: "正文",
+		},
+		{
+			name:    "空 front matter",
+			text:    "---\n---\n# 标题\n正文",
+			title:   "标题",
+			content: "正文",
+		},
+		{
+			name:    "空 front matter 且无正文",
+			text:    "---\n---",
+			content: "",
+		},
+		{
+			name:    "逗号分隔的标签",
+			text:    "---\ntitle: 你好\ntags: a, b\nid: 123\n---\n正文",
+			title:   "你好",
+			tags:    []string{"a", "b"},
+			content: "正文",
+			id:      "123",
+		},
+		{
+			name:    "行内列表标签",
+			text:    "---\ntitle: \"你好\"\ntags: [a, \"b\"]\n---\n正文",
+			title:   "你好",
+			tags:    []string{"a", "b"},
+			content: "正文",
+		},
+		{
+			name:    "块列表标签",
+			text:    "---\r\ntitle: 你好\r\ntags:\r\n  - a\r\n  - 'b'\r\nhidden: true\r\n---\r\n正文",
+			title:   "你好",
+			tags:    []string{"a", "b"},
+			content: "正文",
+		},
+		{
+			name:    "front matter 标题优先于一级标题",
+			text:    "---\ntitle: 你好\n---\n# 标题\n正文",
+			title:   "你好",
+			content: "# 标题\n正文",
+		},
+		{
+			name:    "列表项不属于 tags",
+			text:    "---\ntitle: 你好\n- a\n---\n正文",
+			wantErr: "列表项不属于 tags",
+		},
+		{
+			name:    "未闭合的行内列表",
+			text:    "---\ntags: [a, b\n---\n正文",
+			wantErr: "tags 的值无效",
+		},
+		{
+			name:    "缺少结束标记",
+			text:    "---\ntitle: 你好\n正文",
+			wantErr: "缺少结束标记",
+		},
+		{
+			name:    "不支持的字段",
+			text:    "---\nauthor: me\n---\n正文",
+			wantErr: "不支持的字段",
+		},
+		{
+			name:    "非法布尔值",
+			text:    "---\nhidden: maybe\n---\n正文",
+			wantErr: "hidden 的值无效",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft, id, err := parseArticleFile(tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if draft.Title != tt.title {
+				t.Errorf("title = %q, want %q", draft.Title, tt.title)
+			}
+			if !reflect.DeepEqual(draft.Tags, tt.tags) {
+				t.Errorf("tags = %q, want %q", draft.Tags, tt.tags)
+			}
+			if draft.Content != tt.content {
+				t.Errorf("content = %q, want %q", draft.Content, tt.content)
+			}
+			if id != tt.id {
+				t.Errorf("id = %q, want %q", id, tt.id)
+			}
+		})
+	}
+}
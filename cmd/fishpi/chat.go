@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"dpbug/fishpi/go-client/internal/config"
+	"dpbug/fishpi/go-client/pkg/fishpi"
+)
+
+// chatBlockTimeout 显示等阻塞订阅者的最长等待时间，远小于聊天室连接的空闲超时
+const chatBlockTimeout = 10 * time.Second
+
+func runChatCommand(client *fishpi.Client, args []string) {
+	if len(args) == 0 {
+		printCommandUsage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "nodes":
+		listChatNodes(client)
+	case "record":
+		recordChat(client, args[1:])
+	case "replay":
+		replayChat(client, args[1:])
+	case "join-leave":
+		setJoinLeave(args[1:])
+	default:
+		fmt.Printf("⚠ 未知命令: chat %s\n\n", args[0])
+		printCommandUsage()
+		os.Exit(2)
+	}
+}
+
+// listChatNodes 列出聊天室节点及其在线人数、握手延迟
+func listChatNodes(client *fishpi.Client) {
+	loginUser(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	probes, err := client.ProbeChatRoomNodes(ctx)
+	if err != nil {
+		fmt.Printf("⚠ 获取聊天室节点失败: %v\n", err)
+		os.Exit(1)
+	}
+	if len(probes) == 0 {
+		fmt.Println("× 没有可选的聊天室节点")
+		return
+	}
+
+	fmt.Printf("%-16s %6s %6s %10s  %s\n", "节点", "在线", "权重", "延迟", "地址")
+	for _, p := range probes {
+		latency := "失败"
+		if p.Err == nil {
+			latency = p.Latency.Round(time.Millisecond).String()
+		}
+		fmt.Printf("%-16s %6d %6d %10s  %s\n", p.Name, p.Online, p.Weight, latency, p.URL)
+	}
+}
+
+// recordChat 连接聊天室并将收到的原始帧录制到 JSONL 文件，Ctrl+C 结束
+func recordChat(client *fishpi.Client, args []string) {
+	fs := flag.NewFlagSet("chat record", flag.ExitOnError)
+	output := fs.String("o", "", "录制文件（默认 chat-时间.jsonl）")
+	fs.Parse(args)
+
+	if *output == "" {
+		*output = fmt.Sprintf("chat-%s.jsonl", time.Now().Format("20060102-150405"))
+	}
+
+	loginUser(client)
+
+	dialCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	conn, err := client.ConnectChatRoom(dialCtx)
+	cancel()
+	if err != nil {
+		fmt.Printf("⚠ 连接聊天室失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	f, err := os.OpenFile(*output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		conn.Close()
+		fmt.Printf("⚠ 创建录制文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("✓ 正在录制聊天室到 %s，按 Ctrl+C 结束\n", *output)
+	runChatHub(ctx, fishpi.NewChatHub(fishpi.NewChatRecorder(conn, f), client.Logger))
+}
+
+// replayChat 回放录制文件，消息经过与实时聊天相同的解码和显示流程
+func replayChat(client *fishpi.Client, args []string) {
+	fs := flag.NewFlagSet("chat replay", flag.ExitOnError)
+	file := fs.String("file", "", "录制文件")
+	speed := fs.Float64("speed", 1, "回放倍速，0 表示不等待")
+	fs.Parse(args)
+
+	if *file == "" {
+		fmt.Println("⚠ 请使用 --file 指定录制文件")
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		fmt.Printf("⚠ 打开录制文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	runChatHub(ctx, fishpi.NewChatHub(fishpi.NewChatReplayer(f, *speed), client.Logger))
+}
+
+// runChatHub 运行 hub 并将消息输出到标准输出，直到来源结束或 ctx 取消
+func runChatHub(ctx context.Context, hub *fishpi.ChatHub) {
+	display := hub.Subscribe(
+		fishpi.WithPolicy(fishpi.Block),
+		fishpi.WithBlockTimeout(chatBlockTimeout),
+		fishpi.WithFilter(joinLeaveFilter(loadHideJoinLeave())),
+	)
+
+	errc := make(chan error, 1)
+	go func() { errc <- hub.Run(ctx) }()
+
+	for e := range display.C {
+		if text := formatChatEvent(e); text != "" {
+			fmt.Println(text)
+		}
+	}
+
+	if err := <-errc; err != nil && ctx.Err() == nil {
+		fmt.Printf("⚠ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// trackOnlineUsers 维护在线用户，在线用户同时加入 @用户名 补全缓存
+func trackOnlineUsers(sub *fishpi.Subscription, online *fishpi.OnlineTracker, console *chatConsole, users *fishpi.UserCache) {
+	for e := range sub.C {
+		diff := online.Observe(e)
+		if diff == nil {
+			continue
+		}
+
+		if diff.Initial {
+			for _, u := range online.Users() {
+				users.Add(u.UserName, "", u.UserAvatarURL)
+			}
+			console.Notify("👥 当前在线 %d 人，输入 /who 查看\n", diff.Count)
+			continue
+		}
+		// 进出提示以服务端推送的 customMessage 为准，由消息显示协程输出，这里只更新补全缓存
+		for _, name := range diff.Joined {
+			users.Add(name, "", "")
+		}
+	}
+}
+
+// printOnlineUsers 列出当前在线用户
+func printOnlineUsers(console *chatConsole, online *fishpi.OnlineTracker) {
+	if online.Updated().IsZero() {
+		console.Printf("尚未收到在线用户列表，请稍后再试\n")
+		return
+	}
+
+	list := online.Users()
+	names := make([]string, 0, len(list))
+	for _, u := range list {
+		names = append(names, u.UserName)
+	}
+	console.Printf("\n👥 在线 %d 人（更新于 %s）:\n%s\n\n",
+		online.Count(), formatClock(online.Updated()), strings.Join(names, "  "))
+}
+
+// setJoinLeave 设置聊天室中是否显示用户进出提示
+func setJoinLeave(args []string) {
+	if len(args) == 0 || (args[0] != "show" && args[0] != "hide") {
+		hide := loadHideJoinLeave()
+		fmt.Printf("用户进出提示: %s（fishpi chat join-leave show|hide 修改）\n", map[bool]string{true: "隐藏", false: "显示"}[hide])
+		return
+	}
+
+	hide := args[0] == "hide"
+	if err := config.SaveHideJoinLeave(hide); err != nil {
+		fmt.Printf("⚠ 保存设置失败: %v\n", err)
+		os.Exit(1)
+	}
+	if hide {
+		fmt.Println("✓ 已隐藏聊天室用户进出提示")
+	} else {
+		fmt.Println("✓ 已显示聊天室用户进出提示")
+	}
+}
+
+// joinLeaveFilter 返回显示订阅的过滤条件，hide 为 true 时过滤掉用户进出提示
+func joinLeaveFilter(hide bool) func(*fishpi.ChatEvent) bool {
+	return func(e *fishpi.ChatEvent) bool {
+		return !hide || !e.IsJoinLeave()
+	}
+}
+
+// loadHideJoinLeave 读取是否隐藏用户进出提示，读取失败时显示
+func loadHideJoinLeave() bool {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return false
+	}
+	return cfg.HideJoinLeave
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"dpbug/fishpi/go-client/internal/config"
+	"dpbug/fishpi/go-client/pkg/fishpi"
+
+	"golang.org/x/term"
+)
+
+// runCommand 执行命令行子命令，如 fishpi mfa set
+func runCommand(client *fishpi.Client, args []string) {
+	switch args[0] {
+	case "mfa":
+		runMFACommand(args[1:])
+	case "article":
+		runArticleCommand(client, args[1:])
+	case "follow":
+		runFollowCommand(client, args[1:])
+	case "points":
+		runPointsCommand(client, args[1:])
+	case "breezemoon":
+		runBreezemoonCommand(client, args[1:])
+	case "chat":
+		runChatCommand(client, args[1:])
+	case "redpacket":
+		runRedPacketCommand(client, args[1:])
+	case "help", "-h", "--help":
+		printCommandUsage()
+	default:
+		fmt.Printf("⚠ 未知命令: %s\n\n", args[0])
+		printCommandUsage()
+		os.Exit(2)
+	}
+}
+
+func printCommandUsage() {
+	fmt.Println("用法:")
+	fmt.Println("  fishpi                 进入交互式菜单")
+	fmt.Println("  fishpi mfa set         加密保存 TOTP 密钥，登录时自动生成二重验证令牌")
+	fmt.Println("  fishpi mfa show        显示当前的二重验证令牌")
+	fmt.Println("  fishpi mfa clear       清除已保存的 TOTP 密钥")
+	fmt.Println("  fishpi article post --file report.md [--tags go,weekly] [--title 标题] [--update 帖子ID]")
+	fmt.Println("                         发布或更新帖子，文件开头可用 front matter 指定标题、标签等信息")
+	fmt.Println("  fishpi follow export [--user 用户名] [--format csv|dot] [--pages N] [-o 文件]")
+	fmt.Println("                         导出粉丝/关注关系图")
+	fmt.Println("  fishpi points transfer --to 用户名 --amount 积分 [--memo 备注]")
+	fmt.Println("                         转账积分（自动校验余额并记账）")
+	fmt.Println("  fishpi points ledger [--days N]")
+	fmt.Println("                         查看本地积分账本（转账、红包收支）")
+	fmt.Println("  fishpi breezemoon serve-feed [--addr :8080] [--interval 5m] [--size 50]")
+	fmt.Println("                         以 Atom/RSS/JSON Feed 提供清风明月订阅源")
+	fmt.Println("  fishpi chat nodes      列出聊天室节点的在线人数和握手延迟")
+	fmt.Println("  fishpi chat record [-o 文件]")
+	fmt.Println("                         录制聊天室原始帧到 JSONL 文件，Ctrl+C 结束")
+	fmt.Println("  fishpi chat replay --file 文件 [--speed 倍速]")
+	fmt.Println("                         离线回放录制文件（--speed 0 不等待）")
+	fmt.Println("  fishpi chat join-leave [show|hide]")
+	fmt.Println("                         显示或隐藏聊天室中的用户进出提示")
+	fmt.Println("  fishpi redpacket stats [--days N] [--list N] [--replay 录制文件]")
+	fmt.Println("                         红包统计：领取名单、我的收益、猜拳战绩")
+	fmt.Println("  fishpi redpacket policy [--min-expected 积分] [--max-loss-ratio 0~1]")
+	fmt.Println("                         查看或设置心跳红包风险阈值，期望收益过低或亏损人数过多时不领取")
+}
+
+func runMFACommand(args []string) {
+	if len(args) == 0 {
+		printCommandUsage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "set":
+		fmt.Print("请输入 TOTP 密钥（Base32，绑定验证器时显示的密钥）: ")
+		secretBytes, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println()
+		if err != nil {
+			fmt.Printf("⚠ 读取 TOTP 密钥失败: %v\n", err)
+			os.Exit(1)
+		}
+		secret := strings.TrimSpace(string(secretBytes))
+
+		// 保存前先校验密钥格式
+		code, err := fishpi.GenerateTOTP(secret, time.Now())
+		if err != nil {
+			fmt.Printf("⚠ %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.SaveMFASecret(secret); err != nil {
+			fmt.Printf("⚠ 保存 TOTP 密钥失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ TOTP 密钥已加密保存，当前令牌: %s\n", code)
+	case "show":
+		secret, err := config.GetMFASecret()
+		if err != nil {
+			fmt.Printf("⚠ 读取 TOTP 密钥失败: %v\n", err)
+			os.Exit(1)
+		}
+		if secret == "" {
+			fmt.Println("× 尚未保存 TOTP 密钥")
+			return
+		}
+		code, err := fishpi.GenerateTOTP(secret, time.Now())
+		if err != nil {
+			fmt.Printf("⚠ %v\n", err)
+			os.Exit(1)
+		}
+		remaining := fishpi.TOTPPeriod - time.Now().Unix()%fishpi.TOTPPeriod
+		fmt.Printf("当前令牌: %s（%d 秒后过期）\n", code, remaining)
+	case "clear":
+		if err := config.SaveMFASecret(""); err != nil {
+			fmt.Printf("⚠ 清除 TOTP 密钥失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ TOTP 密钥已清除")
+	default:
+		fmt.Printf("⚠ 未知命令: mfa %s\n\n", args[0])
+		printCommandUsage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/render"
+
+	"golang.org/x/term"
+)
+
+const chatPrompt = "> "
+
+// chatConsole 聊天室输入输出
+// 标准输入是终端时进入 raw 模式，使用 term.Terminal 编辑输入行，支持 Tab 补全，
+// 并在打印新消息时保留正在输入的内容；否则退化为按行读取。
+type chatConsole struct {
+	terminal *term.Terminal
+	oldState *term.State
+	reader   *bufio.Reader
+	prompt   string // 当前提示符，为空时使用 chatPrompt
+	mu       sync.Mutex
+}
+
+// newChatConsole 创建聊天室终端，complete 为 Tab 补全回调（可为 nil）
+// complete 接收光标前的待补全单词，返回候选列表
+func newChatConsole(complete func(word string) []string) *chatConsole {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return &chatConsole{reader: bufio.NewReader(os.Stdin)}
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return &chatConsole{reader: bufio.NewReader(os.Stdin)}
+	}
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, chatPrompt)
+	if width, height, err := term.GetSize(fd); err == nil {
+		t.SetSize(width, height)
+	}
+
+	c := &chatConsole{terminal: t, oldState: oldState}
+	if complete != nil {
+		t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+			if key != '\t' {
+				return "", 0, false
+			}
+			return c.completeWord(line, pos, complete)
+		}
+	}
+	return c
+}
+
+// completeWord 补全光标前的单词：唯一候选直接补全，多个候选补全公共前缀并列出候选
+func (c *chatConsole) completeWord(line string, pos int, complete func(word string) []string) (string, int, bool) {
+	start := strings.LastIndexAny(line[:pos], " \t") + 1
+	word := line[start:pos]
+	if word == "" {
+		return "", 0, false
+	}
+
+	candidates := complete(word)
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+
+	replacement := candidates[0] + " "
+	if len(candidates) > 1 {
+		replacement = commonPrefix(candidates)
+		if len(replacement) <= len(word) {
+			fmt.Fprintf(c.terminal, "%s\n", strings.Join(candidates, "  "))
+			return "", 0, false
+		}
+	}
+
+	newLine := line[:start] + replacement + line[pos:]
+	return newLine, start + len(replacement), true
+}
+
+// commonPrefix 计算候选列表的公共前缀（忽略大小写比较，保留第一个候选的写法）
+func commonPrefix(words []string) string {
+	prefix := []rune(words[0])
+	for _, w := range words[1:] {
+		r := []rune(w)
+		n := 0
+		for n < len(prefix) && n < len(r) && strings.EqualFold(string(prefix[n]), string(r[n])) {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+// ReadLine 读取一行输入（不含换行符）
+func (c *chatConsole) ReadLine() (string, error) {
+	if c.terminal != nil {
+		return c.terminal.ReadLine()
+	}
+
+	c.mu.Lock()
+	fmt.Print(c.currentPrompt())
+	c.mu.Unlock()
+	line, err := c.reader.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// Confirm 以 question 作为提示符读取一行输入，输入 y 或 yes 时返回 true
+func (c *chatConsole) Confirm(question string) bool {
+	c.mu.Lock()
+	prompt := c.currentPrompt()
+	c.prompt = question + " [y/N] "
+	c.mu.Unlock()
+	if c.terminal != nil {
+		c.terminal.SetPrompt(question + " [y/N] ")
+	}
+
+	defer func() {
+		c.mu.Lock()
+		c.prompt = prompt
+		c.mu.Unlock()
+		if c.terminal != nil {
+			c.terminal.SetPrompt(prompt)
+		}
+	}()
+
+	answer, err := c.ReadLine()
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// Printf 输出一行同步提示（在输入循环中调用）
+func (c *chatConsole) Printf(format string, a ...interface{}) {
+	if c.terminal != nil {
+		fmt.Fprintf(c.terminal, format, a...)
+		return
+	}
+	fmt.Printf(format, a...)
+}
+
+// Notify 在后台协程中输出消息，不打断正在输入的内容
+func (c *chatConsole) Notify(format string, a ...interface{}) {
+	if c.terminal != nil {
+		fmt.Fprintf(c.terminal, format, a...)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// 清除当前行，打印消息后重新显示提示符
+	fmt.Print("\r\033[K")
+	fmt.Printf(format, a...)
+	fmt.Print(c.currentPrompt())
+}
+
+// SetTopic 在提示符前显示聊天室当前话题，topic 为空时恢复默认提示符
+func (c *chatConsole) SetTopic(topic string) {
+	prompt := chatPrompt
+	if topic != "" {
+		prompt = "[#" + topic + "] " + chatPrompt
+	}
+
+	c.mu.Lock()
+	c.prompt = prompt
+	c.mu.Unlock()
+	if c.terminal != nil {
+		c.terminal.SetPrompt(prompt)
+	}
+}
+
+// currentPrompt 返回当前提示符，调用方需持有锁
+func (c *chatConsole) currentPrompt() string {
+	if c.prompt == "" {
+		return chatPrompt
+	}
+	return c.prompt
+}
+
+// Close 恢复终端状态
+func (c *chatConsole) Close() {
+	if c.oldState != nil {
+		term.Restore(int(os.Stdin.Fd()), c.oldState)
+		c.oldState = nil
+	}
+}
+
+// colorEnabled 标准输出为终端且未设置 NO_COLOR 时启用 ANSI 样式
+var colorEnabled = term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("NO_COLOR") == ""
+
+// renderHTML 将消息中的 HTML 内容渲染为终端文本
+func renderHTML(content string) string {
+	return render.Render(content, colorEnabled)
+}
+
+// ansiColor 将 "#rgb"、"#rrggbb"、"rgb(r,g,b)" 或 "rgba(r,g,b,a)" 转为 24 位前景色转义序列
+func ansiColor(color string) (string, bool) {
+	color = strings.ToLower(strings.TrimSpace(color))
+
+	var rgb [3]int64
+	switch {
+	case strings.HasPrefix(color, "#"):
+		hex := color[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 {
+			return "", false
+		}
+		for i := range rgb {
+			v, err := strconv.ParseInt(hex[i*2:i*2+2], 16, 64)
+			if err != nil {
+				return "", false
+			}
+			rgb[i] = v
+		}
+	case strings.HasPrefix(color, "rgb"):
+		start, end := strings.Index(color, "("), strings.Index(color, ")")
+		if start < 0 || end < start {
+			return "", false
+		}
+		parts := strings.Split(color[start+1:end], ",")
+		if len(parts) < 3 {
+			return "", false
+		}
+		for i := range rgb {
+			v, err := strconv.ParseInt(strings.TrimSpace(parts[i]), 10, 64)
+			if err != nil || v < 0 || v > 255 {
+				return "", false
+			}
+			rgb[i] = v
+		}
+	default:
+		return "", false
+	}
+	return fmt.Sprintf("\033[38;2;%d;%d;%dm", rgb[0], rgb[1], rgb[2]), true
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi"
+	"dpbug/fishpi/go-client/pkg/fishpi/feed"
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+)
+
+func runBreezemoonCommand(client *fishpi.Client, args []string) {
+	if len(args) == 0 || args[0] != "serve-feed" {
+		printCommandUsage()
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("breezemoon serve-feed", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "监听地址")
+	interval := fs.Duration("interval", 5*time.Minute, "刷新间隔（不小于 30s）")
+	size := fs.Int("size", 50, "订阅源包含的条目数")
+	fs.Parse(args[1:])
+
+	// 接口有 30 秒的请求间隔限制，刷新过快没有意义
+	if *interval < fishpi.MinRequestInterval*time.Second {
+		*interval = fishpi.MinRequestInterval * time.Second
+	}
+
+	cache := &feedCache{client: client, size: *size}
+	if err := cache.refresh(); err != nil {
+		fmt.Printf("⚠ 获取清风明月失败: %v\n", err)
+	}
+	go cache.refreshLoop(*interval)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/atom.xml", cache.handler("application/atom+xml; charset=utf-8", feed.Atom))
+	mux.HandleFunc("/rss.xml", cache.handler("application/rss+xml; charset=utf-8", feed.RSS))
+	mux.HandleFunc("/feed.json", cache.handler("application/feed+json; charset=utf-8", feed.JSONFeed))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "摸鱼派清风明月订阅源")
+		fmt.Fprintln(w, "  /atom.xml   Atom")
+		fmt.Fprintln(w, "  /rss.xml    RSS 2.0")
+		fmt.Fprintln(w, "  /feed.json  JSON Feed")
+	})
+
+	fmt.Printf("✓ 清风明月订阅源已启动: http://%s/atom.xml （每 %s 刷新）\n", *addr, *interval)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		fmt.Printf("⚠ 订阅源服务退出: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// feedCache 缓存最近的清风明月，定时刷新
+type feedCache struct {
+	client  *fishpi.Client
+	size    int
+	items   []models.Breezemoon
+	updated time.Time
+	mu      sync.RWMutex
+}
+
+func (c *feedCache) refresh() error {
+	result, err := c.client.GetBreezemoons(1, c.size)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.items = result.Breezemoons
+	c.updated = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *feedCache) refreshLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := c.refresh(); err != nil {
+			fmt.Printf("⚠ 刷新清风明月失败: %v\n", err)
+		}
+	}
+}
+
+func (c *feedCache) handler(contentType string, render func([]models.Breezemoon, feed.Options) ([]byte, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.mu.RLock()
+		items, updated := c.items, c.updated
+		c.mu.RUnlock()
+
+		if updated.IsZero() {
+			http.Error(w, "订阅源尚未就绪", http.StatusServiceUnavailable)
+			return
+		}
+
+		data, err := render(items, feed.Options{
+			BaseURL: c.client.BaseURL,
+			SelfURL: "http://" + r.Host + r.URL.Path,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Last-Modified", updated.UTC().Format(http.TimeFormat))
+		w.Write(data)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"dpbug/fishpi/go-client/pkg/fishpi"
+)
+
+func runFollowCommand(client *fishpi.Client, args []string) {
+	if len(args) == 0 || args[0] != "export" {
+		printCommandUsage()
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("follow export", flag.ExitOnError)
+	username := fs.String("user", "", "用户名（默认为当前登录用户）")
+	format := fs.String("format", "csv", "导出格式：csv 或 dot")
+	pages := fs.Int("pages", 0, "粉丝/关注列表各自最多抓取的页数（0 表示全部）")
+	output := fs.String("o", "", "输出文件（默认输出到标准输出）")
+	fs.Parse(args[1:])
+
+	// 数据写到标准输出时，提示信息改为输出到标准错误，避免混入导出内容
+	var out, status io.Writer = os.Stdout, os.Stdout
+	if *output == "" {
+		status = os.Stderr
+	}
+
+	if *format != "csv" && *format != "dot" {
+		fmt.Fprintf(status, "⚠ 不支持的导出格式: %s\n", *format)
+		os.Exit(2)
+	}
+
+	user := loginUserTo(client, status)
+	if *username == "" {
+		*username = user.UserName
+	}
+
+	graph, err := client.FetchFollowGraph(*username, *pages)
+	if err != nil {
+		fmt.Fprintf(status, "⚠ 获取关注关系失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Printf("⚠ 创建输出文件失败: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if *format == "dot" {
+		err = graph.WriteDOT(out)
+	} else {
+		err = graph.WriteCSV(out)
+	}
+	if err != nil {
+		fmt.Fprintf(status, "⚠ 导出失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output != "" {
+		fmt.Printf("✓ 已导出 %d 条关注关系到 %s\n", len(graph.Edges), *output)
+	}
+}
@@ -3,8 +3,9 @@ package main
 import (
 	"bufio"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"math/rand"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"sync"
 	"syscall"
 	"time"
+	"unicode"
 
 	"dpbug/fishpi/go-client/internal/config"
 	"dpbug/fishpi/go-client/pkg/fishpi"
@@ -24,10 +26,6 @@ import (
 )
 
 func main() {
-	fmt.Println("🐟 摸鱼派 Go 客户端")
-	fmt.Println("==================")
-	fmt.Println()
-
 	// 创建日志记录器（仅用于错误日志）
 	logger, err := zap.NewProduction()
 	if err != nil {
@@ -35,26 +33,104 @@ func main() {
 	}
 	defer logger.Sync()
 
+	// 读取已保存的 TOTP 密钥（用于自动生成二重验证码）
+	mfaSecret, err := config.GetMFASecret()
+	if err != nil {
+		fmt.Printf("⚠ 读取 TOTP 密钥失败: %v\n", err)
+	}
+
 	// 创建客户端（启用静默模式，不输出调试日志）
-	client := fishpi.NewClient(
+	opts := []fishpi.ClientOption{
 		fishpi.WithLogger(logger),
 		fishpi.WithSilent(true), // true-启用静默模式, false-调试模式
-	)
+		fishpi.WithMFASecret(mfaSecret),
+	}
+
+	// 积分账本：记录转账和红包收支
+	if ledgerPath, err := config.GetLedgerPath(); err == nil {
+		opts = append(opts, fishpi.WithLedger(fishpi.NewLedger(ledgerPath)))
+	}
+
+	client := fishpi.NewClient(opts...)
+
+	// 带参数运行时执行子命令
+	if len(os.Args) > 1 {
+		runCommand(client, os.Args[1:])
+		return
+	}
+
+	fmt.Println("🐟 摸鱼派 Go 客户端")
+	fmt.Println("==================")
+	fmt.Println()
 
+	user := loginUser(client)
+
+	// 显示用户信息
+	printUserInfo(user)
+
+	// 获取活跃度
+	fmt.Println("\n正在获取活跃度...")
+	liveness, err := client.GetLiveness()
+	if err != nil {
+		fmt.Printf("⚠ 获取活跃度失败: %v\n", err)
+	} else {
+		fmt.Printf("✓ 当前活跃度: %.2f\n", liveness)
+	}
+
+	// 获取签到状态
+	fmt.Println("\n正在获取签到状态...")
+	checkedIn, err := client.GetCheckInStatus()
+	if err != nil {
+		fmt.Printf("⚠ 获取签到状态失败: %v\n", err)
+	} else {
+		if checkedIn {
+			fmt.Println("✓ 今日已签到")
+		} else {
+			fmt.Println("× 今日未签到")
+		}
+	}
+
+	// 尝试领取昨日活跃奖励
+	fmt.Println("\n正在领取昨日活跃奖励...")
+	reward, err := client.ClaimYesterdayLivenessReward()
+	if err != nil {
+		fmt.Printf("⚠ 领取昨日活跃奖励失败: %v\n", err)
+	} else {
+		if reward == -1 {
+			fmt.Println("× 昨日活跃奖励已领取")
+		} else {
+			fmt.Printf("✓ 成功领取昨日活跃奖励: %d 积分\n", reward)
+		}
+	}
+
+	// 显示完整统计信息
+	printSummary(user, liveness, checkedIn, reward)
+
+	// 进入主菜单
+	showMainMenu(client, user)
+}
+
+// loginUser 优先使用已保存的 API Key 登录，失败时交互式输入账号密码登录
+func loginUser(client *fishpi.Client) *models.User {
+	return loginUserTo(client, os.Stdout)
+}
+
+// loginUserTo 同 loginUser，登录过程中的提示信息输出到 w
+func loginUserTo(client *fishpi.Client, w io.Writer) *models.User {
 	// 检查是否已有保存的API Key
 	savedAPIKey, _ := config.GetAPIKey()
 	var user *models.User
 	if savedAPIKey != "" {
-		fmt.Println("检测到已保存的API Key，尝试使用...")
+		fmt.Fprintln(w, "检测到已保存的API Key，尝试使用...")
 		userData, err := client.LoginWithKey(savedAPIKey)
 		if err == nil {
-			fmt.Printf("✓ 使用已保存的API Key登录成功!\n")
+			fmt.Fprintf(w, "✓ 使用已保存的API Key登录成功!\n")
 			user = userData
 			// 继续执行后续操作（活跃度、签到等）
 		} else {
-			fmt.Printf("⚠ 已保存的API Key无效: %v\n", err)
-			fmt.Println("需要重新登录")
-			fmt.Println()
+			fmt.Fprintf(w, "⚠ 已保存的API Key无效: %v\n", err)
+			fmt.Fprintln(w, "需要重新登录")
+			fmt.Fprintln(w)
 		}
 	}
 
@@ -64,7 +140,7 @@ func main() {
 		reader := bufio.NewReader(os.Stdin)
 
 		// 获取用户名
-		fmt.Print("请输入用户名: ")
+		fmt.Fprint(w, "请输入用户名: ")
 		username, err := reader.ReadString('\n')
 		if err != nil {
 			log.Fatalf("读取用户名失败: %v", err)
@@ -74,92 +150,55 @@ func main() {
 			log.Fatal("用户名不能为空")
 		}
 
-		fmt.Print("请输入密码: ")
+		fmt.Fprint(w, "请输入密码: ")
 		passwordBytes, err := term.ReadPassword(int(syscall.Stdin))
 		if err != nil {
 			log.Fatalf("读取密码失败: %v", err)
 		}
-		fmt.Println() // 换行
+		fmt.Fprintln(w) // 换行
 		password := strings.TrimSpace(string(passwordBytes))
 		if password == "" {
 			log.Fatal("密码不能为空")
 		}
 
-		// 获取二重验证令牌（可选）
-		fmt.Print("请输入二重验证令牌（如未开启请直接回车）: ")
-		mfaCode, err := reader.ReadString('\n')
-		if err != nil {
-			log.Fatalf("读取二重验证令牌失败: %v", err)
+		// 获取二重验证令牌（可选，已保存 TOTP 密钥时自动生成）
+		var mfaCode string
+		if client.MFASecret == "" {
+			fmt.Fprint(w, "请输入二重验证令牌（如未开启请直接回车）: ")
+			mfaCode, err = reader.ReadString('\n')
+			if err != nil {
+				log.Fatalf("读取二重验证令牌失败: %v", err)
+			}
+			mfaCode = strings.TrimSpace(mfaCode)
+		} else {
+			fmt.Fprintln(w, "已配置 TOTP 密钥，将自动生成二重验证令牌")
 		}
-		mfaCode = strings.TrimSpace(mfaCode)
 
 		// 执行登录
-		fmt.Printf("\n正在登录用户: %s\n", username)
+		fmt.Fprintf(w, "\n正在登录用户: %s\n", username)
 		apiKey, err := client.Login(username, password, mfaCode)
 		if err != nil {
 			log.Fatalf("登录失败: %v", err)
 		}
 
-		fmt.Printf("✓ 登录成功! API Key: %s...\n", apiKey[:8])
+		fmt.Fprintf(w, "✓ 登录成功! API Key: %s...\n", apiKey[:8])
 
 		// 保存API Key到配置文件
 		if err := config.SaveAPIKey(apiKey); err != nil {
-			fmt.Printf("⚠ 警告: 保存API Key失败: %v\n", err)
+			fmt.Fprintf(w, "⚠ 警告: 保存API Key失败: %v\n", err)
 		} else {
-			fmt.Println("✓ API Key已保存到配置文件")
+			fmt.Fprintln(w, "✓ API Key已保存到配置文件")
 		}
 
 		// 获取用户信息
-		fmt.Println("\n正在获取用户信息...")
+		fmt.Fprintln(w, "\n正在获取用户信息...")
 		user, err = client.GetUser()
 		if err != nil {
 			log.Fatalf("获取用户信息失败: %v", err)
 		}
 	}
 
-	// 显示用户信息
-	printUserInfo(user)
-
-	// 获取活跃度
-	fmt.Println("\n正在获取活跃度...")
-	liveness, err := client.GetLiveness()
-	if err != nil {
-		fmt.Printf("⚠ 获取活跃度失败: %v\n", err)
-	} else {
-		fmt.Printf("✓ 当前活跃度: %.2f\n", liveness)
-	}
-
-	// 获取签到状态
-	fmt.Println("\n正在获取签到状态...")
-	checkedIn, err := client.GetCheckInStatus()
-	if err != nil {
-		fmt.Printf("⚠ 获取签到状态失败: %v\n", err)
-	} else {
-		if checkedIn {
-			fmt.Println("✓ 今日已签到")
-		} else {
-			fmt.Println("× 今日未签到")
-		}
-	}
-
-	// 尝试领取昨日活跃奖励
-	fmt.Println("\n正在领取昨日活跃奖励...")
-	reward, err := client.ClaimYesterdayLivenessReward()
-	if err != nil {
-		fmt.Printf("⚠ 领取昨日活跃奖励失败: %v\n", err)
-	} else {
-		if reward == -1 {
-			fmt.Println("× 昨日活跃奖励已领取")
-		} else {
-			fmt.Printf("✓ 成功领取昨日活跃奖励: %d 积分\n", reward)
-		}
-	}
-
-	// 显示完整统计信息
-	printSummary(user, liveness, checkedIn, reward)
-
-	// 进入主菜单
-	showMainMenu(client, user)
+	return user
 }
 
 func showMainMenu(client *fishpi.Client, user *models.User) {
@@ -184,9 +223,9 @@ func showMainMenu(client *fishpi.Client, user *models.User) {
 
 		switch choice {
 		case "1":
-			enterChatRoom(client)
+			enterChatRoom(client, user)
 		case "2":
-			enterBreezemoon(client)
+			enterBreezemoon(client, user)
 		case "3":
 			printUserInfo(user)
 		case "0":
@@ -198,7 +237,7 @@ func showMainMenu(client *fishpi.Client, user *models.User) {
 	}
 }
 
-func enterChatRoom(client *fishpi.Client) {
+func enterChatRoom(client *fishpi.Client, user *models.User) {
 	fmt.Println("\n" + strings.Repeat("=", 50))
 	fmt.Println("💬 进入聊天室")
 	fmt.Println(strings.Repeat("=", 50))
@@ -220,21 +259,73 @@ func enterChatRoom(client *fishpi.Client) {
 	fmt.Println("✓ 聊天室连接成功！")
 	fmt.Println("\n使用说明：")
 	fmt.Println("- 直接输入文字发送消息")
+	fmt.Println("- 输入 @ 加用户名前缀后按 Tab 补全用户名")
+	fmt.Println("- 输入 /upload <文件路径> 上传并发送图片")
+	fmt.Println("- 输入 /barrage [--yes] [颜色] <内容> 发送弹幕（发送前确认花费）")
+	fmt.Println("- 输入 /topic <话题> 修改聊天室话题，当前话题显示在输入提示符前")
+	fmt.Println("- 输入 /who 查看在线用户")
 	fmt.Println("- 红包会自动领取（30秒间隔，猜拳随机出拳）")
 	fmt.Println("- 输入 /exit 或 /quit 退出聊天室")
 	fmt.Println()
 
-	// 启动消息接收协程（带自动领取红包）
-	stopReceive := make(chan struct{})
-	go receiveChatMessagesWithClient(conn, client, stopReceive)
+	// 最近出现的用户，用于 @用户名 补全
+	users := fishpi.NewUserCache(0)
+	console := newChatConsole(newMentionCompleter(client, users))
+	defer console.Close()
+
+	// 读循环由 hub 独占，消息显示和自动领取红包分别订阅
+	hubCtx, stopReceive := context.WithCancel(context.Background())
+	defer stopReceive()
+	hub := fishpi.NewChatHub(conn, client.Logger)
+	display := hub.Subscribe(
+		fishpi.WithPolicy(fishpi.Block),
+		fishpi.WithBlockTimeout(chatBlockTimeout),
+		fishpi.WithFilter(joinLeaveFilter(loadHideJoinLeave())),
+	)
+	redPackets := hub.Subscribe(
+		fishpi.WithFilter(func(e *fishpi.ChatEvent) bool { return e.Message.IsRedPacket() }),
+		fishpi.WithPolicy(fishpi.DropOldest),
+		fishpi.WithBufferSize(8),
+	)
+	redPacketEvents := hub.Subscribe(
+		fishpi.WithFilter(func(e *fishpi.ChatEvent) bool {
+			return e.Type == models.ChatTypeRedPacketStatus || e.Message.IsRedPacket()
+		}),
+		fishpi.WithPolicy(fishpi.Block),
+		fishpi.WithBlockTimeout(chatBlockTimeout),
+	)
+
+	online := fishpi.NewOnlineTracker()
+	onlineEvents := hub.Subscribe(
+		fishpi.WithTypes(models.ChatTypeOnline),
+		fishpi.WithPolicy(fishpi.DropOldest),
+		fishpi.WithBufferSize(4),
+	)
+
+	// 红包跟踪记录，退出聊天室时保存，供 fishpi redpacket stats 查看
+	tracker := loadRedPacketTracker(user.UserName)
+	defer saveRedPacketTracker(tracker)
+
+	go displayChatMessages(display, console, users)
+	go trackOnlineUsers(onlineEvents, online, console, users)
+	go grabRedPackets(redPackets, client, console, tracker, loadRedPacketPolicy())
+	go trackRedPackets(redPacketEvents, tracker, console)
+	go func() {
+		err := hub.Run(hubCtx)
+		if errors.Is(err, fishpiws.ErrStaleConnection) {
+			console.Notify("\n⚠ 聊天室连接已失效（长时间未收到数据），请退出后重新进入: %v\n", err)
+		} else if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+			console.Notify("\n⚠ WebSocket 连接断开: %v\n", err)
+		}
+	}()
 
 	// 主循环：发送消息
-	reader := bufio.NewReader(os.Stdin)
 	for {
-		fmt.Print("> ")
-		input, err := reader.ReadString('\n')
+		input, err := console.ReadLine()
 		if err != nil {
-			fmt.Printf("\n⚠ 读取输入失败: %v\n", err)
+			if err != io.EOF {
+				console.Printf("\n⚠ 读取输入失败: %v\n", err)
+			}
 			break
 		}
 		input = strings.TrimSpace(input)
@@ -246,117 +337,321 @@ func enterChatRoom(client *fishpi.Client) {
 		// 处理命令
 		if strings.HasPrefix(input, "/") {
 			if input == "/exit" || input == "/quit" {
-				fmt.Println("\n👋 正在退出聊天室...")
-				close(stopReceive)
+				console.Printf("\n👋 正在退出聊天室...\n")
+				stopReceive()
 				time.Sleep(100 * time.Millisecond)
 				return
 			} else if input == "/help" {
-				fmt.Println("\n可用命令：")
-				fmt.Println("  /exit, /quit - 退出聊天室")
-				fmt.Println()
+				console.Printf("\n可用命令：\n")
+				console.Printf("  /upload <文件路径> - 上传图片/文件并发送到聊天室\n")
+				console.Printf("  /barrage [--yes] [颜色] <内容> - 发送弹幕（消耗积分，颜色如 #ff6600 或 rgba(255,102,0,1)，--yes 跳过确认）\n")
+				console.Printf("  /topic <话题> - 修改聊天室话题\n")
+				console.Printf("  /who - 查看在线用户\n")
+				console.Printf("  /exit, /quit - 退出聊天室\n")
+				console.Printf("\n")
+				continue
+			} else if input == "/who" {
+				printOnlineUsers(console, online)
+				continue
+			} else if strings.HasPrefix(input, "/topic ") {
+				if err := client.SetDiscussTopic(strings.TrimPrefix(input, "/topic ")); err != nil {
+					console.Printf("⚠ %v\n", err)
+				}
+				continue
+			} else if strings.HasPrefix(input, "/barrage ") {
+				sendBarrage(client, console, strings.TrimSpace(strings.TrimPrefix(input, "/barrage ")))
+				continue
+			} else if strings.HasPrefix(input, "/upload ") {
+				uploadAndSend(client, console, strings.TrimSpace(strings.TrimPrefix(input, "/upload ")))
 				continue
 			} else {
-				fmt.Printf("⚠ 未知命令: %s (输入 /help 查看帮助)\n", input)
+				console.Printf("⚠ 未知命令: %s (输入 /help 查看帮助)\n", input)
 				continue
 			}
 		}
 
 		// 发送消息
 		if err := client.SendChatMessage(input); err != nil {
-			fmt.Printf("⚠ 发送消息失败: %v\n", err)
+			console.Printf("⚠ 发送消息失败: %v\n", err)
 		}
 	}
 
-	close(stopReceive)
+	stopReceive()
 }
 
-func receiveChatMessagesWithClient(conn *fishpiws.ChatRoomConn, client *fishpi.Client, stop chan struct{}) {
-	var lastRedPacketTime time.Time
-	var redPacketMu sync.Mutex
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+// sendBarrage 发送弹幕，参数以 # 或 rgb( / rgba( 开头时作为颜色
+func sendBarrage(client *fishpi.Client, console *chatConsole, args string) {
+	// --yes/-y 跳过花费确认
+	confirmed := false
+	if fields := strings.Fields(args); len(fields) > 0 && (fields[0] == "--yes" || fields[0] == "-y") {
+		confirmed = true
+		args = strings.TrimSpace(strings.TrimPrefix(args, fields[0]))
+	}
 
-	for {
-		select {
-		case <-stop:
+	// 颜色在确认花费之前校验，避免确认后才发现颜色无效
+	color, content, err := parseBarrageArgs(args)
+	if err != nil {
+		console.Printf("⚠ %v\n", err)
+		return
+	}
+	if content == "" {
+		console.Printf("⚠ 用法: /barrage [--yes] [颜色] <内容>\n")
+		return
+	}
+
+	if !confirmed {
+		question := "发送弹幕将消耗积分（花费未知），确认发送？"
+		if cost, err := client.GetBarrageCost(); err == nil {
+			question = fmt.Sprintf("发送弹幕将花费 %d %s，确认发送？", cost.Cost, cost.Unit)
+		}
+		if !console.Confirm(question) {
+			console.Printf("已取消发送弹幕\n")
 			return
-		default:
-			_, message, err := conn.Conn.ReadMessage()
-			if err != nil {
-				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
-					fmt.Printf("\n⚠ WebSocket 连接断开: %v\n", err)
-				}
-				return
+		}
+	}
+	if err := client.SendBarrage(content, color); err != nil {
+		console.Printf("⚠ %v\n", err)
+	}
+}
+
+// parseBarrageArgs 拆分弹幕参数中的颜色和内容
+// 颜色可以是 #rgb、#rrggbb，或 rgb(r,g,b)、rgba(r,g,b,a)（括号内允许空格），
+// 只有颜色没有内容时整段作为内容。返回的颜色去掉了空白。
+func parseBarrageArgs(args string) (color, content string, err error) {
+	args = strings.TrimSpace(args)
+	lower := strings.ToLower(args)
+
+	var token string
+	switch {
+	case strings.HasPrefix(lower, "rgb(") || strings.HasPrefix(lower, "rgba("):
+		end := strings.Index(args, ")")
+		if end < 0 {
+			return "", "", fmt.Errorf("无效的弹幕颜色: 缺少右括号")
+		}
+		token = args[:end+1]
+	case strings.HasPrefix(lower, "#"):
+		token = args
+		if i := strings.IndexFunc(args, unicode.IsSpace); i >= 0 {
+			token = args[:i]
+		}
+	default:
+		return "", args, nil
+	}
+
+	rest := strings.TrimSpace(args[len(token):])
+	if rest == "" {
+		return "", args, nil
+	}
+	color = strings.Join(strings.Fields(token), "")
+	if _, ok := ansiColor(color); !ok {
+		return "", "", fmt.Errorf("无效的弹幕颜色: %s", token)
+	}
+	return color, rest, nil
+}
+
+// uploadAndSend 上传文件并将 Markdown 链接发送到聊天室
+func uploadAndSend(client *fishpi.Client, console *chatConsole, path string) {
+	path = strings.Trim(path, `"'`)
+	if path == "" {
+		console.Printf("⚠ 用法: /upload <文件路径>\n")
+		return
+	}
+
+	console.Printf("正在上传 %s ...\n", path)
+	files, err := client.UploadFiles(path)
+	if err != nil {
+		console.Printf("⚠ 上传失败: %v\n", err)
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	if err := client.SendChatMessage(files[0].Markdown); err != nil {
+		console.Printf("⚠ 发送消息失败: %v\n", err)
+		console.Printf("文件地址: %s\n", files[0].URL)
+	}
+}
+
+// newMentionCompleter 创建 @用户名 补全回调
+// 优先使用最近出现用户的缓存；缓存未命中时在后台查询服务端，下次按 Tab 即可补全，
+// 避免接口请求间隔限制阻塞输入。
+func newMentionCompleter(client *fishpi.Client, users *fishpi.UserCache) func(word string) []string {
+	var mu sync.Mutex
+	searched := make(map[string]bool)
+
+	return func(word string) []string {
+		if !strings.HasPrefix(word, "@") || len(word) < 2 {
+			return nil
+		}
+		prefix := word[1:]
+
+		names := users.Complete(prefix, 10)
+		if len(names) == 0 {
+			key := strings.ToLower(prefix)
+			mu.Lock()
+			if !searched[key] {
+				searched[key] = true
+				go func() {
+					if suggestions, err := client.SearchUsernames(prefix); err == nil {
+						users.AddSuggestions(suggestions)
+					}
+				}()
 			}
+			mu.Unlock()
+			return nil
+		}
 
-			var msg models.ChatMessage
-			if err := json.Unmarshal(message, &msg); err != nil {
-				fmt.Printf("\r\033[K[解析失败] 错误: %v\n原始消息: %s\n> ", err, string(message))
-				continue
+		for i, name := range names {
+			names[i] = "@" + name
+		}
+		return names
+	}
+}
+
+// displayChatMessages 显示聊天消息并记录最近出现的用户
+func displayChatMessages(sub *fishpi.Subscription, console *chatConsole, users *fishpi.UserCache) {
+	topic := ""
+	for e := range sub.C {
+		users.Observe(e.Message)
+
+		// 话题显示在输入提示符前，连接后的 online 帧带有当前话题
+		if t, ok := e.Topic(); ok && t != topic {
+			topic = t
+			console.SetTopic(topic)
+			if e.Type == models.ChatTypeOnline && topic != "" {
+				console.Notify("📌 当前话题: %s\n", topic)
 			}
+		}
 
-			// 清除当前行并打印消息
-			fmt.Print("\r\033[K")
-			printChatMessage(&msg)
-
-			// 自动领取红包（30s间隔限制）
-			if msg.IsRedPacket() {
-				redPacketMu.Lock()
-				elapsed := time.Since(lastRedPacketTime)
-				if elapsed < 30*time.Second {
-					redPacketMu.Unlock()
-					fmt.Printf("⚠ 红包领取冷却中，还需等待 %.0f 秒\n", (30*time.Second - elapsed).Seconds())
-					fmt.Print("> ")
-					continue
-				}
-				lastRedPacketTime = time.Now()
-				redPacketMu.Unlock()
-
-				// 解析红包内容以确定类型
-				rp, err := msg.GetRedPacket()
-				if err != nil {
-					fmt.Printf("⚠ 红包解析失败，跳过自动领取: %v\n", err)
-					fmt.Print("> ")
-					continue
-				}
+		if text := formatChatEvent(e); text != "" {
+			console.Notify("%s\n", text)
+		}
+	}
+}
 
-				// 根据红包类型决定gesture参数
-				gesture := -1
-				if rp.Type == "rockPaperScissors" {
-					gesture = rng.Intn(3) // 随机出拳：0=石头，1=剪刀，2=布
-				}
+// grabRedPackets 自动领取红包（30s间隔限制）
+func grabRedPackets(sub *fishpi.Subscription, client *fishpi.Client, console *chatConsole, tracker *fishpi.RedPacketTracker, policy fishpi.RedPacketPolicy) {
+	var lastRedPacketTime time.Time
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-				// 异步领取红包
-				go func(oId string, g int) {
-					time.Sleep(100 * time.Millisecond)
-					if result, err := client.OpenRedPacket(oId, g); err == nil {
-						gestureName := ""
-						if g >= 0 {
-							gestureNames := []string{"石头", "剪刀", "布"}
-							gestureName = fmt.Sprintf("，出了%s", gestureNames[g])
-						}
-						fmt.Printf("\r\033[K✓ 自动领取红包成功%s！祝福语: %s\n> ", gestureName, result.Data.Msg)
-					} else {
-						fmt.Printf("\r\033[K⚠ 自动领取红包失败: %v\n> ", err)
-					}
-				}(msg.OID, gesture)
+	for e := range sub.C {
+		msg := e.Message
+
+		// 解析红包内容以确定类型
+		rp, err := msg.GetRedPacket()
+		if err != nil {
+			console.Notify("⚠ 红包解析失败，跳过自动领取: %v\n", err)
+			continue
+		}
+
+		// 心跳红包可能扣积分，评估期望收益后再决定是否领取
+		// 发送帧只反映发出时的状态，合并已收到的领取状态；心跳红包再获取一次最新的领取名单
+		live := tracker.Live(msg.OID, rp)
+		if rp.Type == "heartbeat" {
+			if fresh, err := fetchRedPacket(client, msg.OID); err == nil {
+				live = tracker.Live(msg.OID, fresh)
+			} else {
+				console.Notify("⚠ 获取红包最新状态失败，按已知状态评估: %v\n", err)
 			}
-			fmt.Print("> ")
 		}
+		if decision := client.ShouldOpenRedPacket(msg.OID, live, policy); !decision.Open {
+			console.Notify("⚠ 跳过%s红包: %s\n", getRedPacketTypeName(rp.Type), decision.Reason)
+			continue
+		}
+
+		elapsed := time.Since(lastRedPacketTime)
+		if elapsed < 30*time.Second {
+			console.Notify("⚠ 红包领取冷却中，还需等待 %.0f 秒\n", (30*time.Second - elapsed).Seconds())
+			continue
+		}
+		lastRedPacketTime = time.Now()
+
+		// 根据红包类型决定gesture参数
+		gesture := -1
+		if rp.Type == "rockPaperScissors" {
+			gesture = rng.Intn(3) // 随机出拳：0=石头，1=剪刀，2=布
+		}
+
+		// 异步领取红包
+		go func(oId string, g int) {
+			time.Sleep(100 * time.Millisecond)
+			if result, err := client.OpenRedPacket(oId, g); err == nil {
+				p := tracker.RecordOpen(oId, g, result)
+				gestureName := ""
+				if g >= 0 {
+					gestureNames := []string{"石头", "剪刀", "布"}
+					gestureName = fmt.Sprintf("，出了%s（%s）", gestureNames[g], gestureResultName(p.GestureResult))
+				}
+				console.Notify("✓ 自动领取红包成功%s！获得 %d 积分，祝福语: %s\n", gestureName, p.MyAmount, result.Data.Msg)
+				saveRedPacketTracker(tracker)
+			} else {
+				console.Notify("⚠ 自动领取红包失败: %v\n", err)
+			}
+		}(msg.OID, gesture)
+	}
+}
+
+// fetchRedPacket 获取红包消息的最新内容（含当前的领取名单）
+func fetchRedPacket(client *fishpi.Client, oId string) (*models.RedPacketContent, error) {
+	msg, err := client.GetChatMessage(oId)
+	if err != nil {
+		return nil, err
+	}
+	return msg.GetRedPacket()
+}
+
+// formatChatEvent 格式化聊天室事件，返回空字符串表示不需要显示
+func formatChatEvent(e *fishpi.ChatEvent) string {
+	if b, ok := e.Barrage(); ok {
+		return formatBarrage(b, e.Received)
+	}
+	if d, ok := e.DiscussChanged(); ok {
+		return fmt.Sprintf("[%s] 📌 %s 将话题修改为: %s", formatClock(e.Received), d.WhoChanged, d.NewDiscuss)
+	}
+	if m, ok := e.CustomMessage(); ok {
+		return formatNotice(e.Received, "📢 "+m.Message)
+	}
+	if r, ok := e.Revoke(); ok {
+		return formatNotice(e.Received, fmt.Sprintf("↩ 消息 %s 已被撤回", r.OID))
+	}
+	if e.Type != "" && e.Type != models.ChatTypeMessage {
+		// 其他系统推送（online、redPacketStatus 等）由各自的订阅者处理
+		return ""
+	}
+	if e.Message == nil {
+		return ""
 	}
+	return formatChatMessage(e.Message)
 }
 
-func printChatMessage(msg *models.ChatMessage) {
-	// 格式化时间 - time 字段已经是格式化后的字符串（如 "2025-10-29 10:49:55"）
-	// 只需要提取时间部分（HH:MM:SS）
-	timestamp := msg.Time
-	if len(timestamp) > 10 {
-		// 从 "2025-10-29 10:49:55" 提取 "10:49:55"
-		timestamp = timestamp[11:]
+// formatNotice 格式化系统提示，终端支持颜色时以暗色显示，与聊天消息区分
+func formatNotice(received time.Time, text string) string {
+	line := fmt.Sprintf("[%s] %s", formatClock(received), text)
+	if colorEnabled {
+		return "\033[2m" + line + "\033[0m"
 	}
-	// 如果时间超过8个字符（HH:MM:SS），只保留 HH:MM
-	if len(timestamp) > 8 {
-		timestamp = timestamp[:5]
+	return line
+}
+
+// formatBarrage 格式化弹幕，终端支持颜色时使用弹幕颜色显示
+func formatBarrage(b *models.Barrage, received time.Time) string {
+	nickname := b.UserNickname
+	if nickname == "" {
+		nickname = b.UserName
+	}
+	content := b.Content
+	if colorEnabled {
+		if code, ok := ansiColor(b.Color); ok {
+			content = code + content + "\033[0m"
+		}
 	}
+	return fmt.Sprintf("[%s] 🎉 [弹幕] %s: %s", formatClock(received), nickname, content)
+}
+
+// formatChatMessage 格式化聊天消息，返回空字符串表示不需要显示
+func formatChatMessage(msg *models.ChatMessage) string {
+	// 格式化时间，只显示时间部分（HH:MM:SS）
+	timestamp := formatClock(msg.CreatedAt())
 
 	// 判断是否为红包消息
 	if msg.IsRedPacket() {
@@ -368,9 +663,7 @@ func printChatMessage(msg *models.ChatMessage) {
 			if nickname == "" {
 				nickname = "未知用户"
 			}
-			fmt.Printf("[%s] [红包] %s: [红包解析失败: %v]\n", timestamp, nickname, err)
-			fmt.Printf("原始内容: %s\n", msg.Content)
-			return
+			return fmt.Sprintf("[%s] [红包] %s: [红包解析失败: %v]\n原始内容: %s", timestamp, nickname, err, msg.Content)
 		}
 
 		// 显示红包信息
@@ -379,20 +672,19 @@ func printChatMessage(msg *models.ChatMessage) {
 			nickname = "未知用户"
 		}
 		redPacketType := getRedPacketTypeName(rp.Type)
-		fmt.Printf("[%s] [红包] %s: %s (%s红包, %d/%d已领取, 总计%d积分)\n",
+		return fmt.Sprintf("[%s] [红包] %s: %s (%s红包, %d/%d已领取, 总计%d积分)",
 			timestamp, nickname, rp.Msg, redPacketType, rp.Got, rp.Count, rp.Money)
-		return
 	}
 
-	// 普通消息 - 优先使用 Markdown 格式，如果没有则使用 HTML 内容
-	content := msg.MD
+	// 普通消息 - 将 HTML 内容渲染为终端文本，没有 HTML 时使用 Markdown 原文
+	content := renderHTML(msg.Content)
 	if content == "" {
-		content = msg.Content
+		content = msg.MD
 	}
 
 	// 过滤空消息（可能是心跳、系统消息等）
 	if strings.TrimSpace(content) == "" && strings.TrimSpace(msg.UserNickname) == "" {
-		return
+		return ""
 	}
 
 	// 如果昵称为空但有内容，使用默认昵称
@@ -401,7 +693,23 @@ func printChatMessage(msg *models.ChatMessage) {
 		nickname = "系统"
 	}
 
-	fmt.Printf("[%s] %s: %s\n", timestamp, nickname, content)
+	return fmt.Sprintf("[%s] %s: %s", timestamp, nickname, content)
+}
+
+// formatClock 格式化为 HH:MM:SS，零值显示为占位符
+func formatClock(t time.Time) string {
+	if t.IsZero() {
+		return "--:--:--"
+	}
+	return t.Format("15:04:05")
+}
+
+// formatDateTime 格式化为 YYYY-MM-DD HH:MM，零值显示为占位符
+func formatDateTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
 }
 
 func getRedPacketTypeName(rpType string) string {
@@ -491,7 +799,7 @@ func printSummary(user interface{}, liveness float64, checkedIn bool, reward int
 	fmt.Println()
 }
 
-func enterBreezemoon(client *fishpi.Client) {
+func enterBreezemoon(client *fishpi.Client, user *models.User) {
 	reader := bufio.NewReader(os.Stdin)
 
 	for {
@@ -500,6 +808,7 @@ func enterBreezemoon(client *fishpi.Client) {
 		fmt.Println(strings.Repeat("=", 50))
 		fmt.Println("1. 查看清风明月列表")
 		fmt.Println("2. 发布清风明月")
+		fmt.Println("3. 管理我的清风明月")
 		fmt.Println("0. 返回主菜单")
 		fmt.Print("\n请选择功能: ")
 
@@ -515,6 +824,8 @@ func enterBreezemoon(client *fishpi.Client) {
 			viewBreezemoons(client, reader)
 		case "2":
 			postBreezemoon(client, reader)
+		case "3":
+			manageMyBreezemoons(client, user, reader)
 		case "0":
 			return
 		default:
@@ -528,48 +839,48 @@ func viewBreezemoons(client *fishpi.Client, reader *bufio.Reader) {
 	fmt.Println("📜 清风明月列表")
 	fmt.Println(strings.Repeat("=", 50))
 
-	// 获取页码
-	fmt.Print("\n请输入页码 (默认1): ")
-	pageInput, _ := reader.ReadString('\n')
-	pageInput = strings.TrimSpace(pageInput)
-	page := 1
-	if pageInput != "" {
-		fmt.Sscanf(pageInput, "%d", &page)
-	}
+	const batch = 10
+	it := client.Breezemoons(context.Background())
+	count := 0
 
-	// 获取每页数量
-	fmt.Print("请输入每页显示数量 (默认20): ")
-	sizeInput, _ := reader.ReadString('\n')
-	sizeInput = strings.TrimSpace(sizeInput)
-	size := 20
-	if sizeInput != "" {
-		fmt.Sscanf(sizeInput, "%d", &size)
-	}
-
-	fmt.Printf("\n正在获取第 %d 页清风明月 (每页%d条)...\n", page, size)
-
-	result, err := client.GetBreezemoons(page, size)
-	if err != nil {
-		fmt.Printf("⚠ 获取清风明月列表失败: %v\n", err)
-		fmt.Println("\n按回车键继续...")
-		reader.ReadString('\n')
-		return
-	}
+	for {
+		fmt.Println("\n正在获取清风明月...")
 
-	if len(result.Breezemoons) == 0 {
-		fmt.Println("\n暂无清风明月")
-	} else {
-		fmt.Printf("\n共获取到 %d 条清风明月:\n", len(result.Breezemoons))
-		fmt.Println(strings.Repeat("-", 50))
+		shown := 0
+		for shown < batch {
+			bm, err := it.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				fmt.Printf("⚠ 获取清风明月列表失败: %v\n", err)
+				return
+			}
 
-		for i, bm := range result.Breezemoons {
-			fmt.Printf("\n[%d] %s (@%s)\n", i+1, bm.TimeAgo, bm.BreezemoonAuthorName)
+			count++
+			shown++
+			fmt.Printf("\n[%d] %s %s (@%s)\n", count, formatDateTime(bm.CreatedAt()), bm.TimeAgo, bm.BreezemoonAuthorName)
 			if bm.BreezemoonCity != "" {
 				fmt.Printf("📍 %s\n", bm.BreezemoonCity)
 			}
-			fmt.Printf("💬 %s\n", bm.BreezemoonContent)
+			fmt.Printf("💬 %s\n", renderHTML(bm.BreezemoonContent))
 			fmt.Println(strings.Repeat("-", 50))
 		}
+
+		if shown < batch {
+			if count == 0 {
+				fmt.Println("\n暂无清风明月")
+			} else {
+				fmt.Println("\n没有更多清风明月了")
+			}
+			break
+		}
+
+		fmt.Print("\n按回车键加载更多，输入 q 返回: ")
+		input, _ := reader.ReadString('\n')
+		if strings.TrimSpace(input) == "q" {
+			return
+		}
 	}
 
 	fmt.Println("\n按回车键继续...")
@@ -597,12 +908,70 @@ func postBreezemoon(client *fishpi.Client, reader *bufio.Reader) {
 	}
 
 	fmt.Println("\n正在发布清风明月...")
-	if err := client.PostBreezemoon(content); err != nil {
+	bm, err := client.PostBreezemoon(content)
+	switch {
+	case err != nil:
 		fmt.Printf("⚠ 发布失败: %v\n", err)
-	} else {
-		fmt.Println("✓ 发布成功！")
+	case bm == nil:
+		fmt.Println("✓ 发布成功！（未能获取新清风明月的 ID）")
+	default:
+		fmt.Printf("✓ 发布成功！(ID: %s)\n", bm.OID)
 	}
 
 	fmt.Println("\n按回车键继续...")
 	reader.ReadString('\n')
 }
+
+func manageMyBreezemoons(client *fishpi.Client, user *models.User, reader *bufio.Reader) {
+	fmt.Println("\n" + strings.Repeat("=", 50))
+	fmt.Println("🗂  我的清风明月")
+	fmt.Println(strings.Repeat("=", 50))
+
+	fmt.Println("\n正在获取我的清风明月...")
+	result, err := client.GetUserBreezemoons(user.UserName, 1, 10)
+	if err != nil {
+		fmt.Printf("⚠ 获取清风明月列表失败: %v\n", err)
+		return
+	}
+	if len(result.Breezemoons) == 0 {
+		fmt.Println("\n暂无清风明月")
+		return
+	}
+
+	for i, bm := range result.Breezemoons {
+		fmt.Printf("\n[%d] %s %s\n", i+1, formatDateTime(bm.CreatedAt()), bm.TimeAgo)
+		fmt.Printf("💬 %s\n", renderHTML(bm.BreezemoonContent))
+	}
+
+	fmt.Print("\n请输入要管理的序号 (直接回车返回): ")
+	input, _ := reader.ReadString('\n')
+	var index int
+	if _, err := fmt.Sscanf(strings.TrimSpace(input), "%d", &index); err != nil || index < 1 || index > len(result.Breezemoons) {
+		return
+	}
+	bm := result.Breezemoons[index-1]
+
+	fmt.Print("输入 e 修改，d 删除，其他键取消: ")
+	action, _ := reader.ReadString('\n')
+	switch strings.TrimSpace(action) {
+	case "e":
+		fmt.Print("请输入新内容: ")
+		content, _ := reader.ReadString('\n')
+		content = strings.TrimSpace(content)
+		if content == "" {
+			fmt.Println("已取消修改")
+			return
+		}
+		if err := client.UpdateBreezemoon(bm.OID, content); err != nil {
+			fmt.Printf("⚠ 修改失败: %v\n", err)
+		} else {
+			fmt.Println("✓ 修改成功！")
+		}
+	case "d":
+		if err := client.DeleteBreezemoon(bm.OID); err != nil {
+			fmt.Printf("⚠ 删除失败: %v\n", err)
+		} else {
+			fmt.Println("✓ 删除成功！")
+		}
+	}
+}
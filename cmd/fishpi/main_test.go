@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi"
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+)
+
+// chatEvent 按 ChatHub 的方式由原始帧构造事件
+func chatEvent(t *testing.T, raw string) *fishpi.ChatEvent {
+	t.Helper()
+	var msg models.ChatMessage
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		t.Fatal(err)
+	}
+	return &fishpi.ChatEvent{
+		Type:     msg.Type,
+		Message:  &msg,
+		Raw:      []byte(raw),
+		Received: time.Date(2024, 10, 27, 12, 0, 0, 0, models.ServerLocation),
+	}
+}
+
+func TestFormatChatEvent(t *testing.T) {
+	defer func(enabled bool) { colorEnabled = enabled }(colorEnabled)
+	colorEnabled = false
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "进出提示",
+			raw:  `{"type":"customMessage","message":"alice 进入了聊天室"}`,
+			want: "[12:00:00] 📢 alice 进入了聊天室",
+		},
+		{
+			name: "撤回",
+			raw:  `{"type":"revoke","oId":"1730000000000"}`,
+			want: "[12:00:00] ↩ 消息 1730000000000 已被撤回",
+		},
+		{
+			name: "弹幕无昵称时使用用户名",
+			raw:  `{"type":"barrager","userName":"bob","barragerContent":"大家好","barragerColor":"#ff6600"}`,
+			want: "[12:00:00] 🎉 [弹幕] bob: 大家好",
+		},
+		{
+			name: "话题变更",
+			raw:  `{"type":"discussChanged","newDiscuss":"摸鱼","whoChanged":"carol"}`,
+			want: "[12:00:00] 📌 carol 将话题修改为: 摸鱼",
+		},
+		{
+			name: "消息无昵称时显示为系统",
+			raw:  `{"type":"msg","userName":"","userNickname":"","content":"<p>维护通知</p>","time":"2024-10-27 11:33:20"}`,
+			want: "[11:33:20] 系统: 维护通知",
+		},
+		{
+			name: "普通消息",
+			raw:  `{"type":"msg","userName":"alice","userNickname":"爱丽丝","content":"<p>你好</p>","time":"2024-10-27 11:33:20"}`,
+			want: "[11:33:20] 爱丽丝: 你好",
+		},
+		{
+			name: "无昵称且无内容的消息不显示",
+			raw:  `{"type":"msg","content":""}`,
+		},
+		{
+			name: "在线用户推送由其他订阅者处理",
+			raw:  `{"type":"online","onlineChatCnt":3}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatChatEvent(chatEvent(t, tt.raw)); got != tt.want {
+				t.Errorf("formatChatEvent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := formatChatEvent(&fishpi.ChatEvent{Type: models.ChatTypeMessage}); got != "" {
+		t.Errorf("nil Message = %q, want empty", got)
+	}
+}
+
+func TestJoinLeaveFilter(t *testing.T) {
+	joinLeave := chatEvent(t, `{"type":"customMessage","message":"alice 离开了聊天室"}`)
+	message := chatEvent(t, `{"type":"msg","userNickname":"alice","content":"<p>hi</p>"}`)
+
+	tests := []struct {
+		name      string
+		hide      bool
+		event     *fishpi.ChatEvent
+		wantMatch bool
+	}{
+		{name: "显示进出提示", hide: false, event: joinLeave, wantMatch: true},
+		{name: "隐藏进出提示", hide: true, event: joinLeave, wantMatch: false},
+		{name: "隐藏时仍显示消息", hide: true, event: message, wantMatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinLeaveFilter(tt.hide)(tt.event); got != tt.wantMatch {
+				t.Errorf("filter = %v, want %v", got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestParseBarrageArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		color   string
+		content string
+		wantErr string
+	}{
+		{name: "无颜色", args: "大家好", content: "大家好"},
+		{name: "十六进制颜色", args: "#ff6600 大家好", color: "#ff6600", content: "大家好"},
+		{name: "三位十六进制颜色", args: "#F60  大家 好", color: "#F60", content: "大家 好"},
+		{name: "rgba 含空格", args: "rgba(255, 102, 0, 0.5) 大家好", color: "rgba(255,102,0,0.5)", content: "大家好"},
+		{name: "rgb 无空格", args: "RGB(1,2,3) hi", color: "RGB(1,2,3)", content: "hi"},
+		{name: "只有颜色时作为内容", args: "#ff6600", content: "#ff6600"},
+		{name: "只有 rgba 时作为内容", args: "rgba(1, 2, 3, 1)", content: "rgba(1, 2, 3, 1)"},
+		{name: "缺少右括号", args: "rgba(255, 0, 0 大家好", wantErr: "缺少右括号"},
+		{name: "颜色分量越界", args: "rgb(300,0,0) 大家好", wantErr: "无效的弹幕颜色"},
+		{name: "无效的十六进制颜色", args: "#话题 讨论", wantErr: "无效的弹幕颜色"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			color, content, err := parseBarrageArgs(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if color != tt.color || content != tt.content {
+				t.Errorf("got (%q, %q), want (%q, %q)", color, content, tt.color, tt.content)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi"
+)
+
+func runPointsCommand(client *fishpi.Client, args []string) {
+	if len(args) == 0 {
+		printCommandUsage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "transfer":
+		fs := flag.NewFlagSet("points transfer", flag.ExitOnError)
+		to := fs.String("to", "", "收款用户名")
+		amount := fs.Int("amount", 0, "转账积分")
+		memo := fs.String("memo", "", "转账备注")
+		fs.Parse(args[1:])
+
+		loginUser(client)
+
+		fmt.Printf("\n正在向 %s 转账 %d 积分...\n", *to, *amount)
+		if err := client.TransferPoints(*to, *amount, *memo); err != nil {
+			fmt.Printf("⚠ 转账失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ 转账成功！")
+	case "ledger":
+		fs := flag.NewFlagSet("points ledger", flag.ExitOnError)
+		days := fs.Int("days", 0, "只统计最近 N 天（0 表示全部）")
+		fs.Parse(args[1:])
+
+		printLedger(client.Ledger, *days)
+	default:
+		fmt.Printf("⚠ 未知命令: points %s\n\n", args[0])
+		printCommandUsage()
+		os.Exit(2)
+	}
+}
+
+func printLedger(ledger *fishpi.Ledger, days int) {
+	if ledger == nil {
+		fmt.Println("⚠ 未配置积分账本")
+		return
+	}
+
+	var since time.Time
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	entries, err := ledger.Entries()
+	if err != nil {
+		fmt.Printf("⚠ 读取账本失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Println("📒 积分账本")
+	fmt.Println(strings.Repeat("=", 50))
+
+	for _, e := range entries {
+		if !since.IsZero() && e.Time.Before(since) {
+			continue
+		}
+		fmt.Printf("%s  %-14s %+6d  %s %s\n",
+			e.Time.Format("2006-01-02 15:04:05"), ledgerKindName(e.Kind), e.Amount, e.Counterparty, e.Memo)
+	}
+
+	summary, err := ledger.Summarize(since)
+	if err != nil {
+		fmt.Printf("⚠ 汇总账本失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Printf("共 %d 条流水，收入 %d 积分，支出 %d 积分，净变动 %+d 积分\n",
+		summary.Entries, summary.Income, summary.Outgo, summary.Income-summary.Outgo)
+	fmt.Printf("账本文件: %s\n", ledger.Path())
+}
+
+func ledgerKindName(kind string) string {
+	switch kind {
+	case fishpi.LedgerTransferOut:
+		return "转账支出"
+	case fishpi.LedgerRedPacketIn:
+		return "领取红包"
+	default:
+		return kind
+	}
+}
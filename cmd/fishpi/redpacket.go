@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"dpbug/fishpi/go-client/internal/config"
+	"dpbug/fishpi/go-client/pkg/fishpi"
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+)
+
+func runRedPacketCommand(client *fishpi.Client, args []string) {
+	if len(args) == 0 {
+		printCommandUsage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "stats":
+		runRedPacketStats(client, args[1:])
+	case "policy":
+		runRedPacketPolicy(args[1:])
+	default:
+		fmt.Printf("⚠ 未知命令: redpacket %s\n\n", args[0])
+		printCommandUsage()
+		os.Exit(2)
+	}
+}
+
+func runRedPacketStats(client *fishpi.Client, args []string) {
+	fs := flag.NewFlagSet("redpacket stats", flag.ExitOnError)
+	days := fs.Int("days", 0, "只统计最近 N 天发出的红包（0 表示全部）")
+	list := fs.Int("list", 10, "列出最近 N 个红包")
+	replay := fs.String("replay", "", "从聊天室录制文件统计（fishpi chat record 生成）")
+	fs.Parse(args)
+
+	var tracker *fishpi.RedPacketTracker
+	if *replay != "" {
+		tracker = replayRedPackets(client, *replay)
+	} else {
+		tracker = loadRedPacketTracker("")
+	}
+
+	var since time.Time
+	if *days > 0 {
+		since = time.Now().AddDate(0, 0, -*days)
+	}
+	printRedPacketStats(tracker, since, *list)
+}
+
+// runRedPacketPolicy 查看或设置心跳红包风险控制阈值
+func runRedPacketPolicy(args []string) {
+	fs := flag.NewFlagSet("redpacket policy", flag.ExitOnError)
+	minExpected := fs.Float64("min-expected", fishpi.DefaultHeartbeatMinExpected, "心跳红包期望收益下限（积分）")
+	maxLossRatio := fs.Float64("max-loss-ratio", fishpi.DefaultHeartbeatMaxLossRatio, "已领取者中亏损比例上限（0~1）")
+	fs.Parse(args)
+
+	var setMin, setLoss *float64
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "min-expected":
+			setMin = minExpected
+		case "max-loss-ratio":
+			setLoss = maxLossRatio
+		}
+	})
+
+	if setLoss != nil && (*setLoss < 0 || *setLoss > 1) {
+		fmt.Println("⚠ --max-loss-ratio 必须在 0 到 1 之间")
+		os.Exit(2)
+	}
+	if setMin != nil || setLoss != nil {
+		if err := config.SaveHeartbeatPolicy(setMin, setLoss); err != nil {
+			fmt.Printf("⚠ 保存红包策略失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ 红包策略已保存")
+	}
+
+	policy := loadRedPacketPolicy()
+	fmt.Printf("心跳红包期望收益下限: %.1f 积分\n", policy.HeartbeatMinExpected)
+	fmt.Printf("心跳红包亏损比例上限: %.0f%%\n", policy.HeartbeatMaxLossRatio*100)
+}
+
+// loadRedPacketPolicy 从配置加载红包领取策略，未配置的阈值使用默认值
+func loadRedPacketPolicy() fishpi.RedPacketPolicy {
+	policy := fishpi.DefaultRedPacketPolicy()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return policy
+	}
+	if cfg.HeartbeatMinExpected != nil {
+		policy.HeartbeatMinExpected = *cfg.HeartbeatMinExpected
+	}
+	if cfg.HeartbeatMaxLossRatio != nil {
+		policy.HeartbeatMaxLossRatio = *cfg.HeartbeatMaxLossRatio
+	}
+	return policy
+}
+
+// replayRedPackets 回放录制文件，仅统计红包事件
+func replayRedPackets(client *fishpi.Client, path string) *fishpi.RedPacketTracker {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("⚠ 打开录制文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	tracker := fishpi.NewRedPacketTracker("")
+	hub := fishpi.NewChatHub(fishpi.NewChatReplayer(f, 0), client.Logger)
+	sub := hub.Subscribe(fishpi.WithPolicy(fishpi.Block))
+
+	errc := make(chan error, 1)
+	go func() { errc <- hub.Run(context.Background()) }()
+	for e := range sub.C {
+		tracker.Observe(e)
+	}
+	if err := <-errc; err != nil {
+		fmt.Printf("⚠ 回放录制文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	return tracker
+}
+
+func printRedPacketStats(tracker *fishpi.RedPacketTracker, since time.Time, list int) {
+	packets := tracker.Packets()
+	if list > 0 && len(packets) > 0 {
+		fmt.Println("\n=== 最近的红包 ===")
+		start := len(packets) - list
+		if start < 0 {
+			start = 0
+		}
+		for _, p := range packets[start:] {
+			if !since.IsZero() && p.SentAt.Before(since) {
+				continue
+			}
+			status := fmt.Sprintf("%d/%d", p.Got, p.Count)
+			if p.Exhausted() {
+				status += " 已领完"
+			}
+			mine := "-"
+			if p.Opened {
+				mine = fmt.Sprintf("%+d", p.MyAmount)
+				if p.GestureResult != fishpi.GestureResultNone {
+					mine += " " + gestureResultName(p.GestureResult)
+				}
+			}
+			fmt.Printf("%s  %-6s %-12s %5d积分  %-10s 我: %-8s %s\n",
+				formatClock(p.SentAt), getRedPacketTypeName(p.Type), p.Sender, p.Money, status, mine, p.Msg)
+			if len(p.Grabs) > 0 {
+				grabs := make([]string, 0, len(p.Grabs))
+				for _, g := range p.Grabs {
+					if g.Known {
+						grabs = append(grabs, fmt.Sprintf("%s(%d)", g.UserName, g.Amount))
+					} else {
+						grabs = append(grabs, g.UserName)
+					}
+				}
+				fmt.Printf("          领取: %s\n", strings.Join(grabs, ", "))
+			}
+		}
+	}
+
+	summary := tracker.Summarize(since)
+	fmt.Println("\n=== 红包统计 ===")
+	fmt.Printf("观察到红包: %d 个（已领完 %d 个", summary.Seen, summary.Exhausted)
+	if summary.AvgDrain > 0 {
+		fmt.Printf("，平均 %s 领完", summary.AvgDrain.Round(time.Second))
+	}
+	fmt.Println("）")
+	fmt.Printf("我领取了: %d 个，领到积分 %d 次，合计 %+d 积分\n", summary.Opened, summary.Won, summary.MyTotal)
+	if summary.BestOID != "" {
+		fmt.Printf("手气最佳: %d 积分（红包 %s）\n", summary.BestAmount, summary.BestOID)
+	}
+	if len(summary.Gestures) > 0 {
+		fmt.Printf("猜拳战绩: 赢 %d / 平 %d / 输 %d\n",
+			summary.Gestures[fishpi.GestureResultWin],
+			summary.Gestures[fishpi.GestureResultDraw],
+			summary.Gestures[fishpi.GestureResultLose])
+	}
+	if top := summary.TopSenders(5); len(top) > 0 {
+		parts := make([]string, 0, len(top))
+		for _, name := range top {
+			parts = append(parts, fmt.Sprintf("%s(%d)", name, summary.BySender[name]))
+		}
+		fmt.Printf("发红包最多: %s\n", strings.Join(parts, ", "))
+	}
+}
+
+// trackRedPackets 跟踪红包状态，红包领完时提示
+func trackRedPackets(sub *fishpi.Subscription, tracker *fishpi.RedPacketTracker, console *chatConsole) {
+	for e := range sub.C {
+		p := tracker.Observe(e)
+		if p == nil || e.Type != models.ChatTypeRedPacketStatus || !p.Exhausted() || p.Got != p.Count {
+			continue
+		}
+
+		sender := p.Sender
+		if sender == "" {
+			sender = "未知用户"
+		}
+		elapsed := ""
+		if !p.SentAt.IsZero() {
+			elapsed = fmt.Sprintf("，用时 %s", p.ExhaustedAt.Sub(p.SentAt).Round(time.Second))
+		}
+		console.Notify("🧧 %s 的红包已被领完（%d 个%s）\n", sender, p.Count, elapsed)
+	}
+}
+
+// loadRedPacketTracker 加载本地红包记录，失败时返回空的跟踪器
+func loadRedPacketTracker(me string) *fishpi.RedPacketTracker {
+	path, err := config.GetRedPacketPath()
+	if err != nil {
+		return fishpi.NewRedPacketTracker(me)
+	}
+	tracker, err := fishpi.LoadRedPacketTracker(path, me)
+	if err != nil {
+		fmt.Printf("⚠ %v\n", err)
+		return fishpi.NewRedPacketTracker(me)
+	}
+	return tracker
+}
+
+func saveRedPacketTracker(tracker *fishpi.RedPacketTracker) {
+	path, err := config.GetRedPacketPath()
+	if err != nil {
+		return
+	}
+	tracker.Save(path)
+}
+
+func gestureResultName(result string) string {
+	switch result {
+	case fishpi.GestureResultWin:
+		return "赢"
+	case fishpi.GestureResultDraw:
+		return "平"
+	case fishpi.GestureResultLose:
+		return "输"
+	default:
+		return ""
+	}
+}
@@ -12,6 +12,13 @@ type Config struct {
 	BaseURL   string `json:"base_url"`
 	UserAgent string `json:"user_agent"`
 	APIKey    string `json:"api_key"`
+	MFASecret string `json:"mfa_secret,omitempty"` // 加密后的 TOTP 密钥
+
+	// 心跳红包风险控制，未设置时使用默认值
+	HeartbeatMinExpected  *float64 `json:"heartbeat_min_expected,omitempty"`   // 期望收益下限
+	HeartbeatMaxLossRatio *float64 `json:"heartbeat_max_loss_ratio,omitempty"` // 亏损比例上限（0~1）
+
+	HideJoinLeave bool `json:"hide_join_leave,omitempty"` // 聊天室中隐藏用户进出提示
 }
 
 // DefaultConfig 默认配置
@@ -102,3 +109,80 @@ func GetAPIKey() (string, error) {
 	return config.APIKey, nil
 }
 
+// SaveMFASecret 加密保存 TOTP 密钥到配置，传空字符串表示清除
+func SaveMFASecret(secret string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		config = DefaultConfig()
+	}
+
+	if secret == "" {
+		config.MFASecret = ""
+		return SaveConfig(config)
+	}
+
+	encrypted, err := encryptSecret(secret)
+	if err != nil {
+		return err
+	}
+	config.MFASecret = encrypted
+	return SaveConfig(config)
+}
+
+// GetMFASecret 从配置读取并解密 TOTP 密钥，未配置时返回空字符串
+func GetMFASecret() (string, error) {
+	config, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	if config.MFASecret == "" {
+		return "", nil
+	}
+	return decryptSecret(config.MFASecret)
+}
+
+// GetLedgerPath 获取积分账本文件路径
+func GetLedgerPath() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "ledger.jsonl"), nil
+}
+
+// GetRedPacketPath 获取红包统计文件路径
+func GetRedPacketPath() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "redpackets.json"), nil
+}
+
+// SaveHeartbeatPolicy 保存心跳红包风险控制阈值，传 nil 表示保持不变
+func SaveHeartbeatPolicy(minExpected, maxLossRatio *float64) error {
+	config, err := LoadConfig()
+	if err != nil {
+		config = DefaultConfig()
+	}
+
+	if minExpected != nil {
+		config.HeartbeatMinExpected = minExpected
+	}
+	if maxLossRatio != nil {
+		config.HeartbeatMaxLossRatio = maxLossRatio
+	}
+	return SaveConfig(config)
+}
+
+// SaveHideJoinLeave 保存是否在聊天室中隐藏用户进出提示
+func SaveHideJoinLeave(hide bool) error {
+	config, err := LoadConfig()
+	if err != nil {
+		config = DefaultConfig()
+	}
+
+	config.HideJoinLeave = hide
+	return SaveConfig(config)
+}
@@ -0,0 +1,418 @@
+package fishpi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
+	"go.uber.org/zap"
+)
+
+// GetRecentArticles 获取最近帖子列表
+// page: 页码（从1开始）
+// size: 每页显示数量
+func (c *Client) GetRecentArticles(page, size int) (*models.ArticleList, error) {
+	return c.getArticleList("/api/articles/recent", page, size)
+}
+
+// GetHotArticles 获取热门帖子列表
+func (c *Client) GetHotArticles(page, size int) (*models.ArticleList, error) {
+	return c.getArticleList("/api/articles/recent/hot", page, size)
+}
+
+// GetArticlesByTag 获取指定标签下的帖子列表
+// tag: 标签 URI，如 "Go"、"摸鱼"
+func (c *Client) GetArticlesByTag(tag string, page, size int) (*models.ArticleList, error) {
+	if tag == "" {
+		return nil, fmt.Errorf("标签不能为空")
+	}
+	return c.getArticleList("/api/articles/tag/"+url.PathEscape(tag), page, size)
+}
+
+// GetArticlesByDomain 获取指定领域下的帖子列表
+// domain: 领域 URI，如 "play"、"tech"
+func (c *Client) GetArticlesByDomain(domain string, page, size int) (*models.ArticleList, error) {
+	if domain == "" {
+		return nil, fmt.Errorf("领域不能为空")
+	}
+	return c.getArticleList("/api/articles/domain/"+url.PathEscape(domain), page, size)
+}
+
+// getArticleList 请求帖子列表类接口
+func (c *Client) getArticleList(basePath string, page, size int) (*models.ArticleList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 20
+	}
+
+	path := fmt.Sprintf("%s?p=%d&size=%d", basePath, page, size)
+	c.Logger.Info("获取帖子列表",
+		zap.String("path", basePath),
+		zap.Int("page", page),
+		zap.Int("size", size),
+	)
+
+	// 携带 API Key（如已登录）可获取当前用户的投票等状态
+	resp, err := c.doRequest(http.MethodGet, path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.ArticleListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("获取帖子列表失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("获取帖子列表成功",
+		zap.Int("count", len(result.Data.Articles)),
+		zap.Int("page_count", result.Data.Pagination.PaginationPageCount),
+	)
+
+	return &result.Data, nil
+}
+
+// GetArticle 获取帖子详情（包含第一页评论）
+// id: 帖子 oId
+func (c *Client) GetArticle(id string) (*models.ArticleDetail, error) {
+	return c.GetArticleWithComments(id, 1)
+}
+
+// GetArticleWithComments 获取帖子详情及指定页的评论
+// commentPage: 评论页码（从1开始）
+func (c *Client) GetArticleWithComments(id string, commentPage int) (*models.ArticleDetail, error) {
+	if id == "" {
+		return nil, fmt.Errorf("帖子ID不能为空")
+	}
+	if commentPage < 1 {
+		commentPage = 1
+	}
+
+	c.Logger.Info("获取帖子详情", zap.String("id", id), zap.Int("comment_page", commentPage))
+
+	path := fmt.Sprintf("/api/article/%s?p=%d", url.PathEscape(id), commentPage)
+	resp, err := c.doRequest(http.MethodGet, path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.ArticleDetailResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("获取帖子详情失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("获取帖子详情成功",
+		zap.String("title", result.Data.Article.ArticleTitle),
+		zap.Int("comments", len(result.Data.Article.ArticleComments)),
+	)
+
+	return &result.Data, nil
+}
+
+// PostArticle 发布帖子，返回新帖子的 ID
+func (c *Client) PostArticle(draft *models.ArticleDraft) (string, error) {
+	reqBody, err := c.buildArticleRequest(draft)
+	if err != nil {
+		return "", err
+	}
+
+	c.Logger.Info("发布帖子",
+		zap.String("title", draft.Title),
+		zap.String("tags", reqBody.ArticleTags),
+		zap.Int("content_length", len(draft.Content)),
+	)
+
+	// apiKey 已经在请求体中
+	resp, err := c.doRequest(http.MethodPost, "/article", reqBody, false)
+	if err != nil {
+		return "", err
+	}
+
+	var result models.ArticlePostResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return "", err
+	}
+
+	if result.Code != 0 {
+		return "", fmt.Errorf("发布帖子失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("发布帖子成功", zap.String("article_id", result.ArticleID))
+	return result.ArticleID, nil
+}
+
+// UpdateArticle 更新帖子
+// id: 帖子 oId
+func (c *Client) UpdateArticle(id string, draft *models.ArticleDraft) error {
+	if id == "" {
+		return fmt.Errorf("帖子ID不能为空")
+	}
+
+	reqBody, err := c.buildArticleRequest(draft)
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Info("更新帖子",
+		zap.String("id", id),
+		zap.String("title", draft.Title),
+	)
+
+	resp, err := c.doRequest(http.MethodPut, "/article/"+url.PathEscape(id), reqBody, false)
+	if err != nil {
+		return err
+	}
+
+	var result models.ArticleUpdateResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return err
+	}
+
+	if result.Code != 0 {
+		return fmt.Errorf("更新帖子失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("更新帖子成功", zap.String("id", id))
+	return nil
+}
+
+// buildArticleRequest 校验草稿并构建请求体
+func (c *Client) buildArticleRequest(draft *models.ArticleDraft) (*models.ArticleRequest, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("API Key未设置，请先登录")
+	}
+	if draft == nil {
+		return nil, fmt.Errorf("帖子内容不能为空")
+	}
+	if strings.TrimSpace(draft.Title) == "" {
+		return nil, fmt.Errorf("帖子标题不能为空")
+	}
+	if strings.TrimSpace(draft.Content) == "" {
+		return nil, fmt.Errorf("帖子正文不能为空")
+	}
+
+	tags := make([]string, 0, len(draft.Tags))
+	for _, tag := range draft.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("帖子至少需要一个标签")
+	}
+
+	if draft.RewardPoint < 0 {
+		return nil, fmt.Errorf("打赏积分不能为负数")
+	}
+	if draft.RewardPoint > 0 && strings.TrimSpace(draft.RewardContent) == "" {
+		return nil, fmt.Errorf("开启打赏时打赏内容不能为空")
+	}
+
+	showInList := 1
+	if draft.Hidden {
+		showInList = 0
+	}
+
+	return &models.ArticleRequest{
+		APIKey:                 c.APIKey,
+		ArticleTitle:           draft.Title,
+		ArticleContent:         draft.Content,
+		ArticleTags:            strings.Join(tags, ","),
+		ArticleCommentable:     draft.Commentable,
+		ArticleNotifyFollowers: draft.NotifyFollowers,
+		ArticleType:            draft.Type,
+		ArticleShowInList:      showInList,
+		ArticleRewardContent:   draft.RewardContent,
+		ArticleRewardPoint:     draft.RewardPoint,
+		ArticleQnAOfferPoint:   draft.QnAOfferPoint,
+		ArticleAnonymous:       draft.Anonymous,
+	}, nil
+}
+
+// UpvoteArticle 赞同帖子，重复调用会取消赞同
+func (c *Client) UpvoteArticle(id string) (*models.VoteResult, error) {
+	return c.vote("article", id, true)
+}
+
+// DownvoteArticle 反对帖子，重复调用会取消反对
+func (c *Client) DownvoteArticle(id string) (*models.VoteResult, error) {
+	return c.vote("article", id, false)
+}
+
+// vote 对帖子或评论投票
+// dataType: article 或 comment
+func (c *Client) vote(dataType, id string, up bool) (*models.VoteResult, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("API Key未设置，请先登录")
+	}
+	if id == "" {
+		return nil, fmt.Errorf("投票对象ID不能为空")
+	}
+
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+
+	c.Logger.Info("投票",
+		zap.String("type", dataType),
+		zap.String("id", id),
+		zap.String("direction", direction),
+	)
+
+	reqBody := map[string]interface{}{
+		"apiKey": c.APIKey,
+		"dataId": id,
+	}
+
+	resp, err := c.doRequest(http.MethodPost, fmt.Sprintf("/vote/%s/%s", direction, dataType), reqBody, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.VoteResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("投票失败: %s", result.Msg)
+	}
+
+	vote := &models.VoteResult{Up: up, Cancelled: result.Type == -1}
+	c.Logger.Info("投票成功", zap.Bool("cancelled", vote.Cancelled))
+	return vote, nil
+}
+
+// ThankArticle 感谢帖子
+// 感谢会扣除积分，但服务端不返回实际扣除的数额，因此这里不报告消耗积分。
+func (c *Client) ThankArticle(id string) error {
+	if id == "" {
+		return fmt.Errorf("帖子ID不能为空")
+	}
+
+	c.Logger.Info("感谢帖子", zap.String("id", id))
+
+	path := "/article/thank?articleId=" + url.QueryEscape(id)
+	if err := c.postAction(path, map[string]interface{}{}); err != nil {
+		return fmt.Errorf("感谢帖子失败: %w", err)
+	}
+
+	c.Logger.Info("感谢帖子成功")
+	return nil
+}
+
+// RewardArticle 打赏帖子，返回消耗的积分及打赏后可见的内容
+// 消耗积分取打赏前帖子设置的打赏积分，可见内容取自打赏响应。
+func (c *Client) RewardArticle(id string) (*models.PointsResult, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("API Key未设置，请先登录")
+	}
+
+	// 先查询帖子，确认已开启打赏
+	detail, err := c.GetArticle(id)
+	if err != nil {
+		return nil, err
+	}
+	points := detail.Article.ArticleRewardPoint
+	if points <= 0 {
+		return nil, fmt.Errorf("该帖子未开启打赏")
+	}
+
+	c.Logger.Info("打赏帖子", zap.String("id", id), zap.Int("points", points))
+
+	reqBody := map[string]interface{}{"apiKey": c.APIKey}
+
+	// apiKey 已经在请求体中
+	resp, err := c.doRequest(http.MethodPost, "/article/reward?articleId="+url.QueryEscape(id), reqBody, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.RewardResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("打赏帖子失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("打赏帖子成功", zap.Int("points_spent", points))
+	return &models.PointsResult{
+		PointsSpent: points,
+		Content:     result.ArticleRewardContent,
+	}, nil
+}
+
+// FollowArticle 收藏（关注）帖子
+func (c *Client) FollowArticle(id string) error {
+	return c.followArticle("/follow/article", id, "收藏帖子")
+}
+
+// UnfollowArticle 取消收藏帖子
+func (c *Client) UnfollowArticle(id string) error {
+	return c.followArticle("/unfollow/article", id, "取消收藏帖子")
+}
+
+// WatchArticle 关注帖子（有新评论时收到通知）
+func (c *Client) WatchArticle(id string) error {
+	return c.followArticle("/follow/article-watch", id, "关注帖子")
+}
+
+// UnwatchArticle 取消关注帖子
+func (c *Client) UnwatchArticle(id string) error {
+	return c.followArticle("/unfollow/article-watch", id, "取消关注帖子")
+}
+
+func (c *Client) followArticle(path, id, action string) error {
+	if id == "" {
+		return fmt.Errorf("帖子ID不能为空")
+	}
+
+	c.Logger.Info(action, zap.String("id", id))
+
+	if err := c.postAction(path, map[string]interface{}{"followingId": id}); err != nil {
+		return fmt.Errorf("%s失败: %w", action, err)
+	}
+
+	c.Logger.Info(action + "成功")
+	return nil
+}
+
+// postAction 发送携带 apiKey 的互动请求并检查响应状态
+func (c *Client) postAction(path string, reqBody map[string]interface{}) error {
+	if c.APIKey == "" {
+		return fmt.Errorf("API Key未设置，请先登录")
+	}
+	reqBody["apiKey"] = c.APIKey
+
+	// apiKey 已经在请求体中
+	resp, err := c.doRequest(http.MethodPost, path, reqBody, false)
+	if err != nil {
+		return err
+	}
+
+	var result models.ActionResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return err
+	}
+
+	if result.Code != 0 {
+		return errors.New(result.Msg)
+	}
+	return nil
+}
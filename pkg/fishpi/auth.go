@@ -3,6 +3,7 @@ package fishpi
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"dpbug/fishpi/go-client/pkg/fishpi/models"
 	"go.uber.org/zap"
@@ -32,8 +33,19 @@ type UserResponse struct {
 // Login 用户登录，获取API Key
 // nameOrEmail: 用户名或邮箱
 // password: 明文密码（函数内部会自动MD5加密）
-// mfaCode: 两步验证码（如果未设置则留空）
+// mfaCode: 两步验证码（如果未设置则留空；留空且配置了 MFASecret 时自动生成）
 func (c *Client) Login(nameOrEmail, password, mfaCode string) (string, error) {
+	// 未提供验证码但配置了 TOTP 密钥时，自动计算当前验证码
+	// 先等待接口请求间隔再生成，避免验证码在等待期间过期
+	if mfaCode == "" && c.MFASecret != "" {
+		c.waitRateLimit("/api/getKey")
+		code, err := GenerateTOTP(c.MFASecret, time.Now())
+		if err != nil {
+			return "", fmt.Errorf("生成二重验证码失败: %w", err)
+		}
+		mfaCode = code
+	}
+
 	c.Logger.Info("开始登录",
 		zap.String("name_or_email", nameOrEmail),
 		zap.Bool("has_mfa", mfaCode != ""),
@@ -112,6 +124,10 @@ func (c *Client) GetUser() (*models.User, error) {
 		zap.String("nickname", userResp.Data.UserNickname),
 	)
 
+	c.mu.Lock()
+	c.currentUser = userResp.Data
+	c.mu.Unlock()
+
 	return userResp.Data, nil
 }
 
@@ -149,4 +165,3 @@ func (c *Client) LoginWithKey(apiKey string) (*models.User, error) {
 
 	return user, nil
 }
-
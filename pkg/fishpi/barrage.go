@@ -0,0 +1,83 @@
+package fishpi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
+	"go.uber.org/zap"
+)
+
+// barrageCostPattern 弹幕费用页面中的费用说明，如 "发送弹幕每次将花费 <b>5</b> 积分</div>"
+var barrageCostPattern = regexp.MustCompile(`发送弹幕每次将花费\s*<b>([-0-9]+)</b>\s*([^<]*?)\s*</div>`)
+
+// GetBarrageCost 查询发送弹幕的费用
+// 接口返回的是 HTML 片段，从中解析出费用和单位
+func (c *Client) GetBarrageCost() (*models.BarrageCost, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("API Key未设置，请先登录")
+	}
+
+	c.Logger.Info("查询弹幕费用")
+
+	resp, err := c.doRequest(http.MethodGet, "/chat-room/barrager/get", nil, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应体失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+
+	match := barrageCostPattern.FindStringSubmatch(string(body))
+	if match == nil {
+		return nil, fmt.Errorf("解析弹幕费用失败, 响应: %s", string(body))
+	}
+	cost, err := strconv.Atoi(match[1])
+	if err != nil {
+		return nil, fmt.Errorf("解析弹幕费用失败: %w", err)
+	}
+
+	c.Logger.Info("查询弹幕费用成功", zap.Int("cost", cost), zap.String("unit", match[2]))
+	return &models.BarrageCost{Cost: cost, Unit: match[2]}, nil
+}
+
+// SendBarrage 发送弹幕（每次发送会扣除积分，费用见 GetBarrageCost）
+// color 为空时使用白色，支持 "rgba(r,g,b,a)" 和 "#rrggbb" 格式
+func (c *Client) SendBarrage(content, color string) error {
+	if c.APIKey == "" {
+		return fmt.Errorf("API Key未设置，请先登录")
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return fmt.Errorf("弹幕内容不能为空")
+	}
+	if color == "" {
+		color = models.DefaultBarrageColor
+	}
+
+	c.Logger.Info("发送弹幕", zap.String("content", content), zap.String("color", color))
+
+	payload, err := json.Marshal(models.BarragePayload{Color: color, Content: content})
+	if err != nil {
+		return fmt.Errorf("序列化弹幕失败: %w", err)
+	}
+	if err := c.SendChatMessage("[barrager]" + string(payload) + "[/barrager]"); err != nil {
+		return fmt.Errorf("发送弹幕失败: %w", err)
+	}
+
+	c.Logger.Info("发送弹幕成功")
+	return nil
+}
@@ -1,7 +1,15 @@
 package fishpi
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"dpbug/fishpi/go-client/pkg/fishpi/models"
 
@@ -43,15 +51,17 @@ func (c *Client) GetBreezemoons(page, size int) (*models.BreezemoonListResponse,
 	return &result, nil
 }
 
-// PostBreezemoon 发布清风明月
+// PostBreezemoon 发布清风明月，返回新发布的清风明月
 // content: 清风明月内容（可以是Markdown格式）
-func (c *Client) PostBreezemoon(content string) error {
+// 发布接口不返回新条目，发布成功后会查询当前用户的清风明月列表来获取；
+// 发布成功但未能查询到新条目时返回 nil, nil，只有发布失败才会返回错误。
+func (c *Client) PostBreezemoon(content string) (*models.Breezemoon, error) {
 	if content == "" {
-		return fmt.Errorf("清风明月内容不能为空")
+		return nil, fmt.Errorf("清风明月内容不能为空")
 	}
 
 	if c.APIKey == "" {
-		return fmt.Errorf("API Key未设置，请先登录")
+		return nil, fmt.Errorf("API Key未设置，请先登录")
 	}
 
 	c.Logger.Info("发布清风明月",
@@ -63,17 +73,291 @@ func (c *Client) PostBreezemoon(content string) error {
 		"breezemoonContent": content,
 	}
 
+	postedAt := time.Now()
 	resp, err := c.doRequest("POST", "/breezemoon", reqBody, false)
 	if err != nil {
-		return fmt.Errorf("请求失败: %w", err)
+		return nil, fmt.Errorf("请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var result models.BreezemoonPostResponse
 	if err := c.parseResponse(resp, &result); err != nil {
-		return err
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("发布清风明月失败: %s", result.Msg)
 	}
 
 	c.Logger.Info("发布清风明月成功")
+
+	bm, err := c.findPostedBreezemoon(postedAt)
+	if err != nil {
+		c.Logger.Warn("查询新发布的清风明月失败", zap.Error(err))
+		return nil, nil
+	}
+	if bm == nil {
+		c.Logger.Warn("未查询到新发布的清风明月")
+	}
+	return bm, nil
+}
+
+// findPostedBreezemoon 在当前用户的清风明月中查找 postedAt 之后发布的最新一条，没有时返回 nil
+// 列表中的内容是渲染后的 HTML，无法与发布的 Markdown 可靠比较，因此只按作者和时间匹配。
+func (c *Client) findPostedBreezemoon(postedAt time.Time) (*models.Breezemoon, error) {
+	username, err := c.currentUsername()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.GetUserBreezemoons(username, 1, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	// 允许与服务器之间存在一定的时钟误差
+	since := postedAt.Add(-time.Minute)
+	// 列表按时间从新到旧排列
+	for _, bm := range list.Breezemoons {
+		if strings.EqualFold(bm.BreezemoonAuthorName, username) && !bm.CreatedAt().Before(since) {
+			return &bm, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetUserBreezemoons 获取指定用户的清风明月列表
+// page: 页码（从1开始）
+// size: 每页显示数量
+func (c *Client) GetUserBreezemoons(username string, page, size int) (*models.BreezemoonListResponse, error) {
+	if username == "" {
+		return nil, fmt.Errorf("用户名不能为空")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 20
+	}
+
+	path := fmt.Sprintf("/api/user/%s/breezemoons?p=%d&size=%d", url.PathEscape(username), page, size)
+	c.Logger.Info("获取用户清风明月列表",
+		zap.String("username", username),
+		zap.Int("page", page),
+		zap.Int("size", size),
+	)
+
+	resp, err := c.doRequest(http.MethodGet, path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.BreezemoonListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("获取用户清风明月列表失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("获取用户清风明月列表成功",
+		zap.Int("count", len(result.Breezemoons)),
+	)
+
+	return &result, nil
+}
+
+// UpdateBreezemoon 修改清风明月
+// oId: 清风明月 ID
+// content: 新内容（可以是Markdown格式）
+func (c *Client) UpdateBreezemoon(oId, content string) error {
+	if oId == "" {
+		return fmt.Errorf("清风明月ID不能为空")
+	}
+	if content == "" {
+		return fmt.Errorf("清风明月内容不能为空")
+	}
+	if c.APIKey == "" {
+		return fmt.Errorf("API Key未设置，请先登录")
+	}
+
+	c.Logger.Info("修改清风明月", zap.String("oId", oId))
+
+	reqBody := map[string]interface{}{
+		"apiKey":            c.APIKey,
+		"breezemoonContent": content,
+	}
+
+	resp, err := c.doRequest(http.MethodPut, "/breezemoon/"+url.PathEscape(oId), reqBody, false)
+	if err != nil {
+		return err
+	}
+
+	var result models.BreezemoonPostResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return err
+	}
+
+	if result.Code != 0 {
+		return fmt.Errorf("修改清风明月失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("修改清风明月成功", zap.String("oId", oId))
+	return nil
+}
+
+// DeleteBreezemoon 删除清风明月
+// oId: 清风明月 ID
+func (c *Client) DeleteBreezemoon(oId string) error {
+	if oId == "" {
+		return fmt.Errorf("清风明月ID不能为空")
+	}
+	if c.APIKey == "" {
+		return fmt.Errorf("API Key未设置，请先登录")
+	}
+
+	c.Logger.Info("删除清风明月", zap.String("oId", oId))
+
+	resp, err := c.doRequest(http.MethodDelete, "/breezemoon/"+url.PathEscape(oId), nil, true)
+	if err != nil {
+		return err
+	}
+
+	var result models.BreezemoonPostResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return err
+	}
+
+	if result.Code != 0 {
+		return fmt.Errorf("删除清风明月失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("删除清风明月成功", zap.String("oId", oId))
 	return nil
 }
+
+// BreezemoonIterator 清风明月分页迭代器，自动翻页直到没有更多数据
+type BreezemoonIterator struct {
+	client *Client
+	ctx    context.Context
+	page   int
+	size   int
+	buf    []models.Breezemoon
+	done   bool
+}
+
+// Breezemoons 创建清风明月迭代器，从第一页（最新）开始，每页 20 条
+func (c *Client) Breezemoons(ctx context.Context) *BreezemoonIterator {
+	return &BreezemoonIterator{
+		client: c,
+		ctx:    ctx,
+		page:   1,
+		size:   20,
+	}
+}
+
+// PageSize 设置每页请求的数量，需在第一次调用 Next 之前设置
+func (it *BreezemoonIterator) PageSize(size int) *BreezemoonIterator {
+	if size > 0 {
+		it.size = size
+	}
+	return it
+}
+
+// Next 返回下一条清风明月，没有更多数据时返回 io.EOF
+func (it *BreezemoonIterator) Next() (*models.Breezemoon, error) {
+	for len(it.buf) == 0 {
+		if it.done {
+			return nil, io.EOF
+		}
+		if err := it.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := it.client.GetBreezemoons(it.page, it.size)
+		if err != nil {
+			return nil, err
+		}
+		it.page++
+		it.buf = result.Breezemoons
+
+		// 返回数量不足一页说明已经是最后一页
+		if len(result.Breezemoons) < it.size {
+			it.done = true
+		}
+	}
+
+	bm := it.buf[0]
+	it.buf = it.buf[1:]
+	return &bm, nil
+}
+
+// MaxBreezemoonSyncPages 增量同步最多翻阅的页数，防止 sinceOID 对应的条目已被删除时翻遍全部历史
+const MaxBreezemoonSyncPages = 10
+
+// ErrBreezemoonSyncTruncated 增量同步达到翻页上限，返回的结果不完整
+var ErrBreezemoonSyncTruncated = errors.New("增量同步清风明月达到翻页上限，结果不完整")
+
+// SyncBreezemoons 增量同步清风明月，只返回比 sinceOID 更新的条目（按时间从新到旧）
+// sinceOID: 上次同步时最新一条的 oId，为空时只返回第一页
+// 清风明月的 oId 即创建时间的毫秒时间戳，遇到 oId 数值不大于它的条目即停止翻页，
+// 最多翻阅 MaxBreezemoonSyncPages 页，达到上限时返回已获取的条目和 ErrBreezemoonSyncTruncated。
+func (c *Client) SyncBreezemoons(ctx context.Context, sinceOID string) ([]models.Breezemoon, error) {
+	it := c.Breezemoons(ctx)
+
+	if sinceOID == "" {
+		var first []models.Breezemoon
+		for i := 0; i < it.size; i++ {
+			bm, err := it.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			first = append(first, *bm)
+		}
+		return first, nil
+	}
+
+	since, err := strconv.ParseInt(sinceOID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("无效的清风明月 oId: %s", sinceOID)
+	}
+
+	var fresh []models.Breezemoon
+	truncated := false
+	for {
+		// 当前缓冲区读完后才会请求下一页
+		if len(it.buf) == 0 && it.page > MaxBreezemoonSyncPages {
+			c.Logger.Warn("增量同步清风明月达到翻页上限",
+				zap.String("since", sinceOID),
+				zap.Int("pages", MaxBreezemoonSyncPages),
+			)
+			truncated = true
+			break
+		}
+
+		bm, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if oid, err := strconv.ParseInt(bm.OID, 10, 64); err == nil && oid <= since {
+			break
+		}
+		fresh = append(fresh, *bm)
+	}
+
+	c.Logger.Info("同步清风明月完成",
+		zap.String("since", sinceOID),
+		zap.Int("new", len(fresh)),
+	)
+	if truncated {
+		return fresh, ErrBreezemoonSyncTruncated
+	}
+	return fresh, nil
+}
@@ -0,0 +1,68 @@
+package fishpi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
+	"go.uber.org/zap"
+)
+
+// newBreezemoonClient 返回连接到按页提供清风明月的测试服务的客户端，oId 从 newest 开始每条递减 1，共 total 条
+func newBreezemoonClient(t *testing.T, newest int64, total int) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("p"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		var result models.BreezemoonListResponse
+		for i := (page - 1) * size; i < page*size && i < total; i++ {
+			result.Breezemoons = append(result.Breezemoons, models.Breezemoon{
+				OID: strconv.FormatInt(newest-int64(i), 10),
+			})
+		}
+		json.NewEncoder(w).Encode(result)
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(WithBaseURL(srv.URL), WithLogger(zap.NewNop()), WithSilent(true))
+}
+
+func TestSyncBreezemoons(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		since   string
+		want    int
+		wantErr error
+	}{
+		{name: "无 sinceOID 只返回第一页", total: 50, since: "", want: 20},
+		{name: "在 sinceOID 处停止", total: 50, since: "970", want: 30},
+		{name: "数据不足一页", total: 5, since: "0", want: 5},
+		{name: "达到翻页上限", total: 1000, since: "0", want: MaxBreezemoonSyncPages * 20, wantErr: ErrBreezemoonSyncTruncated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newBreezemoonClient(t, 1000, tt.total)
+			got, err := c.SyncBreezemoons(context.Background(), tt.since)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("len = %d, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncBreezemoonsInvalidSince(t *testing.T) {
+	c := newBreezemoonClient(t, 1000, 5)
+	if _, err := c.SyncBreezemoons(context.Background(), "abc"); err == nil {
+		t.Fatal("expected error for non-numeric sinceOID")
+	}
+}
@@ -0,0 +1,458 @@
+package fishpi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+	"dpbug/fishpi/go-client/pkg/fishpi/render"
+
+	"go.uber.org/zap"
+)
+
+// DefaultSubscriberBufferSize 订阅者默认缓冲区大小
+const DefaultSubscriberBufferSize = 64
+
+// SlowConsumerPolicy 订阅者缓冲区已满时的处理策略
+type SlowConsumerPolicy int
+
+const (
+	DropNewest SlowConsumerPolicy = iota // 丢弃新事件（默认）
+	DropOldest                           // 丢弃缓冲区中最旧的事件
+	Block                                // 阻塞读循环直到订阅者取走事件，会拖慢所有订阅者
+)
+
+// Block 策略会暂停 Run 的读循环，期间不会读取连接（包括 pong），
+// 订阅者长时间不取走事件可能触发连接的空闲超时（websocket.ErrStaleConnection）。
+// 读取实时连接时应通过 WithBlockTimeout 限制等待时间。
+
+// ChatEvent 聊天室事件
+type ChatEvent struct {
+	Type     string              // 消息类型，如 "msg"
+	Message  *models.ChatMessage // 解码后的消息
+	Raw      []byte              // 原始帧内容
+	Received time.Time           // 接收时间，回放时为录制时的接收时间
+}
+
+// Barrage 事件为弹幕时返回解码后的弹幕
+func (e *ChatEvent) Barrage() (*models.Barrage, bool) {
+	if e.Type != models.ChatTypeBarrage {
+		return nil, false
+	}
+	var b models.Barrage
+	if err := json.Unmarshal(e.Raw, &b); err != nil {
+		return nil, false
+	}
+	return &b, true
+}
+
+// DiscussChanged 事件为话题变更时返回解码后的变更信息
+func (e *ChatEvent) DiscussChanged() (*models.DiscussChanged, bool) {
+	if e.Type != models.ChatTypeDiscussChanged {
+		return nil, false
+	}
+	var d models.DiscussChanged
+	if err := json.Unmarshal(e.Raw, &d); err != nil {
+		return nil, false
+	}
+	return &d, true
+}
+
+// Topic 返回事件携带的当前话题，来自 discussChanged 帧或连接后推送的 online 帧
+func (e *ChatEvent) Topic() (string, bool) {
+	switch e.Type {
+	case models.ChatTypeDiscussChanged:
+		if d, ok := e.DiscussChanged(); ok {
+			return d.NewDiscuss, true
+		}
+	case models.ChatTypeOnline:
+		if o, ok := e.Online(); ok {
+			return o.Discussing, true
+		}
+	}
+	return "", false
+}
+
+// Online 事件为在线用户推送时返回解码后的在线信息
+func (e *ChatEvent) Online() (*models.OnlineInfo, bool) {
+	if e.Type != models.ChatTypeOnline {
+		return nil, false
+	}
+	var o models.OnlineInfo
+	if err := json.Unmarshal(e.Raw, &o); err != nil {
+		return nil, false
+	}
+	return &o, true
+}
+
+// CustomMessage 事件为进出聊天室提示时返回解码后的提示
+func (e *ChatEvent) CustomMessage() (*models.CustomMessage, bool) {
+	if e.Type != models.ChatTypeCustomMessage {
+		return nil, false
+	}
+	var m models.CustomMessage
+	if err := json.Unmarshal(e.Raw, &m); err != nil {
+		return nil, false
+	}
+	return &m, true
+}
+
+// Revoke 事件为消息撤回时返回解码后的撤回通知
+func (e *ChatEvent) Revoke() (*models.RevokeNotice, bool) {
+	if e.Type != models.ChatTypeRevoke {
+		return nil, false
+	}
+	var r models.RevokeNotice
+	if err := json.Unmarshal(e.Raw, &r); err != nil {
+		return nil, false
+	}
+	return &r, true
+}
+
+// IsJoinLeave 事件是否为用户进出聊天室提示
+func (e *ChatEvent) IsJoinLeave() bool {
+	return e.Type == models.ChatTypeCustomMessage
+}
+
+// text 返回用于关键字匹配的纯文本
+func (e *ChatEvent) text() string {
+	if b, ok := e.Barrage(); ok {
+		return b.Content
+	}
+	if m, ok := e.CustomMessage(); ok {
+		return m.Message
+	}
+	if e.Message == nil {
+		return ""
+	}
+	if e.Message.MD != "" {
+		return e.Message.MD
+	}
+	return render.ToPlain(e.Message.Content)
+}
+
+// SubscribeOption 订阅选项
+type SubscribeOption func(*Subscription)
+
+// WithBufferSize 设置订阅者缓冲区大小
+func WithBufferSize(size int) SubscribeOption {
+	return func(s *Subscription) {
+		if size > 0 {
+			s.bufferSize = size
+		}
+	}
+}
+
+// WithPolicy 设置缓冲区已满时的处理策略
+func WithPolicy(policy SlowConsumerPolicy) SubscribeOption {
+	return func(s *Subscription) {
+		s.policy = policy
+	}
+}
+
+// WithBlockTimeout 设置 Block 策略的最长等待时间，超时后丢弃该事件，0 表示一直等待（默认）
+func WithBlockTimeout(timeout time.Duration) SubscribeOption {
+	return func(s *Subscription) {
+		if timeout > 0 {
+			s.blockTimeout = timeout
+		}
+	}
+}
+
+// WithTypes 只接收指定类型的事件
+func WithTypes(types ...string) SubscribeOption {
+	return WithFilter(func(e *ChatEvent) bool {
+		for _, t := range types {
+			if e.Type == t {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// WithUsers 只接收指定用户发送的事件（不区分大小写）
+func WithUsers(users ...string) SubscribeOption {
+	return WithFilter(func(e *ChatEvent) bool {
+		if e.Message == nil {
+			return false
+		}
+		for _, u := range users {
+			if strings.EqualFold(e.Message.UserName, u) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// WithKeywords 只接收内容包含任一关键字的事件（不区分大小写）
+func WithKeywords(keywords ...string) SubscribeOption {
+	return WithFilter(func(e *ChatEvent) bool {
+		text := strings.ToLower(e.text())
+		for _, kw := range keywords {
+			if strings.Contains(text, strings.ToLower(kw)) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// WithFilter 添加自定义过滤条件，多个过滤条件需同时满足
+func WithFilter(filter func(*ChatEvent) bool) SubscribeOption {
+	return func(s *Subscription) {
+		s.filters = append(s.filters, filter)
+	}
+}
+
+// Subscription 聊天室事件订阅
+type Subscription struct {
+	C <-chan *ChatEvent // 事件通道，订阅取消或读循环结束后关闭
+
+	hub          *ChatHub
+	ch           chan *ChatEvent
+	bufferSize   int
+	policy       SlowConsumerPolicy
+	blockTimeout time.Duration
+	filters      []func(*ChatEvent) bool
+	dropped      uint64
+	done         chan struct{}
+	mu           sync.Mutex // 保护 ch 的发送与关闭
+	closed       bool
+	once         sync.Once
+}
+
+// Dropped 因缓冲区已满而丢弃的事件数
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Unsubscribe 取消订阅并关闭事件通道
+func (s *Subscription) Unsubscribe() {
+	s.hub.remove(s)
+	s.close()
+}
+
+func (s *Subscription) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	})
+}
+
+func (s *Subscription) match(e *ChatEvent) bool {
+	for _, filter := range s.filters {
+		if !filter(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// deliver 按策略投递事件，cancel 关闭时放弃阻塞投递
+func (s *Subscription) deliver(e *ChatEvent, cancel <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.ch <- e:
+		return
+	default:
+	}
+
+	switch s.policy {
+	case Block:
+		var timeout <-chan time.Time
+		if s.blockTimeout > 0 {
+			timer := time.NewTimer(s.blockTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case s.ch <- e:
+		case <-timeout:
+			atomic.AddUint64(&s.dropped, 1)
+		case <-s.done:
+		case <-cancel:
+		}
+	case DropOldest:
+		select {
+		case <-s.ch:
+			atomic.AddUint64(&s.dropped, 1)
+		default:
+		}
+		select {
+		case s.ch <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// frameTimer 由能提供帧原始接收时间的帧来源实现（如 ChatReplayer）
+type frameTimer interface {
+	FrameTime() time.Time
+}
+
+// ChatHub 独占聊天室连接的读循环，将解码后的事件分发给多个订阅者
+// 每个订阅者拥有独立的缓冲区和过滤条件，适合在同一进程中同时运行归档、抢红包和终端界面等。
+type ChatHub struct {
+	conn   FrameSource
+	logger *zap.Logger
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewChatHub 创建事件分发中心，调用 Run 开始读取消息
+// conn 通常为聊天室连接，也可以是 ChatRecorder（边分发边录制）或 ChatReplayer（离线回放）。
+func NewChatHub(conn FrameSource, logger *zap.Logger) *ChatHub {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &ChatHub{
+		conn:   conn,
+		logger: logger,
+		subs:   make(map[*Subscription]struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Subscribe 添加订阅者，可在 Run 之前或运行过程中调用
+func (h *ChatHub) Subscribe(opts ...SubscribeOption) *Subscription {
+	s := &Subscription{
+		hub:        h,
+		bufferSize: DefaultSubscriberBufferSize,
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.ch = make(chan *ChatEvent, s.bufferSize)
+	s.C = s.ch
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	select {
+	case <-h.done:
+		// 读循环已结束，直接返回已关闭的订阅
+		s.close()
+	default:
+		h.subs[s] = struct{}{}
+	}
+	return s
+}
+
+func (h *ChatHub) remove(s *Subscription) {
+	h.mu.Lock()
+	delete(h.subs, s)
+	h.mu.Unlock()
+}
+
+func (h *ChatHub) snapshot() []*Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := make([]*Subscription, 0, len(h.subs))
+	for s := range h.subs {
+		subs = append(subs, s)
+	}
+	return subs
+}
+
+// Run 运行读循环直到连接出错或 ctx 结束，返回时关闭所有订阅者的事件通道。
+// ctx 结束时会关闭底层连接以中断阻塞的读取；帧来源正常结束（如回放完毕）时返回 nil。
+func (h *ChatHub) Run(ctx context.Context) error {
+	defer h.stop()
+
+	stopWatch := make(chan struct{})
+	defer close(stopWatch)
+	go func() {
+		select {
+		case <-ctx.Done():
+			h.conn.Close()
+		case <-stopWatch:
+		}
+	}()
+
+	for {
+		_, data, err := h.conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		// 心跳回复等非 JSON 帧不分发
+		if !json.Valid(data) {
+			h.logger.Debug("忽略非 JSON 帧", zap.ByteString("raw", data))
+			continue
+		}
+
+		var msg models.ChatMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			h.logger.Warn("聊天室消息解析失败", zap.Error(err), zap.ByteString("raw", data))
+			continue
+		}
+
+		received := time.Now()
+		if ft, ok := h.conn.(frameTimer); ok {
+			received = ft.FrameTime()
+		}
+		h.publish(&ChatEvent{Type: msg.Type, Message: &msg, Raw: data, Received: received}, ctx.Done())
+	}
+}
+
+// Publish 将事件分发给所有匹配的订阅者，可用于注入本地生成的事件
+// Message 为空时补上只包含类型的消息，接收时间为空时使用当前时间。
+func (h *ChatHub) Publish(e *ChatEvent) {
+	if e.Message == nil {
+		e.Message = &models.ChatMessage{Type: e.Type}
+	}
+	if e.Received.IsZero() {
+		e.Received = time.Now()
+	}
+	h.publish(e, h.done)
+}
+
+func (h *ChatHub) publish(e *ChatEvent, cancel <-chan struct{}) {
+	for _, s := range h.snapshot() {
+		if s.match(e) {
+			s.deliver(e, cancel)
+		}
+	}
+}
+
+// stop 结束分发并关闭所有订阅
+func (h *ChatHub) stop() {
+	h.once.Do(func() {
+		h.mu.Lock()
+		close(h.done)
+		subs := h.subs
+		h.subs = make(map[*Subscription]struct{})
+		h.mu.Unlock()
+
+		for s := range subs {
+			s.close()
+		}
+	})
+}
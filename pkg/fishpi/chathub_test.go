@@ -0,0 +1,327 @@
+package fishpi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+	"dpbug/fishpi/go-client/pkg/fishpi/websocket"
+)
+
+// fakeFrameSource 按顺序返回预置的帧，帧用完后返回 err（默认 io.EOF）；
+// hold 为 true 时帧用完后阻塞直到 Close。
+type fakeFrameSource struct {
+	frames []string
+	err    error
+	hold   bool
+
+	mu     sync.Mutex
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newFakeFrameSource(frames ...string) *fakeFrameSource {
+	return &fakeFrameSource{frames: frames, closed: make(chan struct{})}
+}
+
+func (f *fakeFrameSource) ReadMessage() (int, []byte, error) {
+	f.mu.Lock()
+	if len(f.frames) > 0 {
+		frame := f.frames[0]
+		f.frames = f.frames[1:]
+		f.mu.Unlock()
+		return websocket.TextFrame, []byte(frame), nil
+	}
+	f.mu.Unlock()
+
+	if f.hold {
+		<-f.closed
+		return 0, nil, io.ErrClosedPipe
+	}
+	if f.err != nil {
+		return 0, nil, f.err
+	}
+	return 0, nil, io.EOF
+}
+
+func (f *fakeFrameSource) Close() error {
+	f.once.Do(func() { close(f.closed) })
+	return nil
+}
+
+// drain 读取订阅通道中的全部事件直到通道关闭
+func drain(t *testing.T, sub *Subscription) []*ChatEvent {
+	t.Helper()
+	var events []*ChatEvent
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				return events
+			}
+			events = append(events, e)
+		case <-timeout:
+			t.Fatal("订阅通道未关闭")
+		}
+	}
+}
+
+func TestChatHubFilters(t *testing.T) {
+	frames := []string{
+		`{"type":"msg","userName":"alice","md":"hello world"}`,
+		`{"type":"msg","userName":"Bob","md":"红包来了"}`,
+		`{"type":"barrager","userName":"carol","barragerContent":"Hello 弹幕"}`,
+		`{"type":"online","onlineChatCnt":3}`,
+		`pong`,
+	}
+
+	tests := []struct {
+		name string
+		opts []SubscribeOption
+		want []string // 期望收到的事件类型
+	}{
+		{name: "无过滤", want: []string{"msg", "msg", "barrager", "online"}},
+		{name: "WithTypes", opts: []SubscribeOption{WithTypes(models.ChatTypeBarrage, models.ChatTypeOnline)}, want: []string{"barrager", "online"}},
+		{name: "WithUsers 不区分大小写", opts: []SubscribeOption{WithUsers("bob")}, want: []string{"msg"}},
+		{name: "WithKeywords 匹配消息和弹幕", opts: []SubscribeOption{WithKeywords("HELLO")}, want: []string{"msg", "barrager"}},
+		{name: "多个过滤条件同时满足", opts: []SubscribeOption{WithTypes(models.ChatTypeMessage), WithKeywords("hello")}, want: []string{"msg"}},
+		{name: "自定义过滤", opts: []SubscribeOption{WithFilter(func(e *ChatEvent) bool { return false })}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewChatHub(newFakeFrameSource(frames...), nil)
+			sub := hub.Subscribe(tt.opts...)
+			if err := hub.Run(context.Background()); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			var got []string
+			for _, e := range drain(t, sub) {
+				got = append(got, e.Type)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestChatHubSlowConsumerPolicy(t *testing.T) {
+	frames := []string{
+		`{"type":"msg","oId":"1"}`,
+		`{"type":"msg","oId":"2"}`,
+		`{"type":"msg","oId":"3"}`,
+	}
+
+	tests := []struct {
+		name        string
+		opts        []SubscribeOption
+		wantOIDs    []string
+		wantDropped uint64
+	}{
+		{name: "DropNewest", opts: []SubscribeOption{WithBufferSize(1)}, wantOIDs: []string{"1"}, wantDropped: 2},
+		{name: "DropOldest", opts: []SubscribeOption{WithBufferSize(1), WithPolicy(DropOldest)}, wantOIDs: []string{"3"}, wantDropped: 2},
+		{name: "Block 超时后丢弃", opts: []SubscribeOption{WithBufferSize(1), WithPolicy(Block), WithBlockTimeout(time.Millisecond)}, wantOIDs: []string{"1"}, wantDropped: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewChatHub(newFakeFrameSource(frames...), nil)
+			sub := hub.Subscribe(tt.opts...)
+			// 订阅者在 Run 结束前不读取
+			if err := hub.Run(context.Background()); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			var got []string
+			for _, e := range drain(t, sub) {
+				got = append(got, e.Message.OID)
+			}
+			if len(got) != len(tt.wantOIDs) || (len(got) > 0 && got[0] != tt.wantOIDs[0]) {
+				t.Errorf("received %v, want %v", got, tt.wantOIDs)
+			}
+			if sub.Dropped() != tt.wantDropped {
+				t.Errorf("Dropped = %d, want %d", sub.Dropped(), tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestChatHubBlockWaitsForConsumer(t *testing.T) {
+	frames := make([]string, 10)
+	for i := range frames {
+		frames[i] = `{"type":"msg"}`
+	}
+	hub := NewChatHub(newFakeFrameSource(frames...), nil)
+	sub := hub.Subscribe(WithBufferSize(1), WithPolicy(Block))
+
+	errc := make(chan error, 1)
+	go func() { errc <- hub.Run(context.Background()) }()
+
+	if got := len(drain(t, sub)); got != len(frames) {
+		t.Errorf("received %d events, want %d", got, len(frames))
+	}
+	if sub.Dropped() != 0 {
+		t.Errorf("Dropped = %d, want 0", sub.Dropped())
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("Run: %v", err)
+	}
+}
+
+func TestChatHubUnsubscribe(t *testing.T) {
+	src := newFakeFrameSource(`{"type":"msg"}`)
+	src.hold = true
+	hub := NewChatHub(src, nil)
+	sub := hub.Subscribe()
+	other := hub.Subscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- hub.Run(ctx) }()
+
+	if e := <-other.C; e.Type != models.ChatTypeMessage {
+		t.Fatalf("unexpected event %+v", e)
+	}
+
+	sub.Unsubscribe()
+	sub.Unsubscribe() // 重复取消不应 panic
+	drain(t, sub)
+	hub.Publish(&ChatEvent{Type: models.ChatTypeMessage})
+	if e := <-other.C; e == nil {
+		t.Fatal("other subscriber did not receive published event")
+	}
+
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run = %v, want context.Canceled", err)
+	}
+	drain(t, other)
+}
+
+func TestChatHubRunClosesSubscriptions(t *testing.T) {
+	srcErr := errors.New("连接断开")
+
+	tests := []struct {
+		name    string
+		src     func() *fakeFrameSource
+		cancel  bool
+		wantErr error
+	}{
+		{name: "来源结束", src: func() *fakeFrameSource { return newFakeFrameSource() }},
+		{name: "来源出错", src: func() *fakeFrameSource {
+			f := newFakeFrameSource()
+			f.err = srcErr
+			return f
+		}, wantErr: srcErr},
+		{name: "ctx 取消", src: func() *fakeFrameSource {
+			f := newFakeFrameSource()
+			f.hold = true
+			return f
+		}, cancel: true, wantErr: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewChatHub(tt.src(), nil)
+			sub := hub.Subscribe(WithPolicy(Block))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+			if err := hub.Run(ctx); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run = %v, want %v", err, tt.wantErr)
+			}
+			drain(t, sub)
+
+			// Run 结束后订阅得到已关闭的通道
+			if _, ok := <-hub.Subscribe().C; ok {
+				t.Error("subscription after Run should be closed")
+			}
+		})
+	}
+}
+
+func TestChatHubPublishNilMessage(t *testing.T) {
+	hub := NewChatHub(nil, nil)
+	byUser := hub.Subscribe(WithUsers("alice"))
+	byKeyword := hub.Subscribe(WithKeywords("hello"))
+	all := hub.Subscribe()
+
+	// 本地注入的事件可能没有 Message，过滤条件不能因此 panic
+	hub.Publish(&ChatEvent{Type: models.ChatTypeMessage})
+	hub.Publish(&ChatEvent{Type: models.ChatTypeMessage, Message: &models.ChatMessage{UserName: "Alice", MD: "Hello world"}})
+
+	if got := len(byUser.C); got != 1 {
+		t.Errorf("WithUsers received %d events, want 1", got)
+	}
+	if got := len(byKeyword.C); got != 1 {
+		t.Errorf("WithKeywords received %d events, want 1", got)
+	}
+	if got := len(all.C); got != 2 {
+		t.Fatalf("unfiltered subscriber received %d events, want 2", got)
+	}
+	if e := <-all.C; e.Message == nil || e.Received.IsZero() {
+		t.Errorf("Publish did not fill Message/Received: %+v", e)
+	}
+}
+
+func TestChatEventNotices(t *testing.T) {
+	tests := []struct {
+		name      string
+		event     *ChatEvent
+		custom    string
+		revoke    string
+		joinLeave bool
+	}{
+		{
+			name:      "进出提示",
+			event:     &ChatEvent{Type: models.ChatTypeCustomMessage, Raw: []byte(`{"type":"customMessage","message":"alice 进入了聊天室"}`)},
+			custom:    "alice 进入了聊天室",
+			joinLeave: true,
+		},
+		{
+			name:   "撤回",
+			event:  &ChatEvent{Type: models.ChatTypeRevoke, Raw: []byte(`{"type":"revoke","oId":"1730000000000"}`)},
+			revoke: "1730000000000",
+		},
+		{
+			name:  "普通消息",
+			event: &ChatEvent{Type: models.ChatTypeMessage, Raw: []byte(`{"type":"msg","message":"x","oId":"1"}`)},
+		},
+		{
+			name:      "无法解析的进出提示",
+			event:     &ChatEvent{Type: models.ChatTypeCustomMessage, Raw: []byte(`not json`)},
+			joinLeave: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, ok := tt.event.CustomMessage()
+			if ok != (tt.custom != "") || (ok && m.Message != tt.custom) {
+				t.Errorf("CustomMessage = %+v, %v, want %q", m, ok, tt.custom)
+			}
+			r, ok := tt.event.Revoke()
+			if ok != (tt.revoke != "") || (ok && r.OID != tt.revoke) {
+				t.Errorf("Revoke = %+v, %v, want %q", r, ok, tt.revoke)
+			}
+			if got := tt.event.IsJoinLeave(); got != tt.joinLeave {
+				t.Errorf("IsJoinLeave = %v, want %v", got, tt.joinLeave)
+			}
+		})
+	}
+}
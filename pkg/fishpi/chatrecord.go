@@ -0,0 +1,160 @@
+package fishpi
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/websocket"
+)
+
+// FrameSource 聊天室原始帧来源，*websocket.ChatRoomConn、ChatRecorder 和 ChatReplayer 均实现该接口
+type FrameSource interface {
+	ReadMessage() (int, []byte, error)
+	Close() error
+}
+
+// RecordedFrame 录制文件中的一行
+type RecordedFrame struct {
+	Time time.Time       `json:"time"`           // 接收时间
+	Data json.RawMessage `json:"data"`           // 原始帧内容（聊天室帧均为 JSON）
+	Text bool            `json:"text,omitempty"` // 原始帧不是 JSON，Data 为保存帧内容的 JSON 字符串
+}
+
+// ChatRecorder 包装帧来源，将读到的每一帧连同接收时间以 JSONL 格式写入 w
+type ChatRecorder struct {
+	src FrameSource
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewChatRecorder 创建录制器，将其作为 ChatHub 的帧来源即可在分发的同时录制
+func NewChatRecorder(src FrameSource, w io.Writer) *ChatRecorder {
+	return &ChatRecorder{src: src, enc: json.NewEncoder(w)}
+}
+
+// ReadMessage 读取下一帧并写入录制文件
+func (r *ChatRecorder) ReadMessage() (int, []byte, error) {
+	messageType, data, err := r.src.ReadMessage()
+	if err != nil {
+		return messageType, data, err
+	}
+
+	// 非 JSON 帧（如纯文本心跳回复）按字符串保存并标记
+	frame := RecordedFrame{Time: time.Now(), Data: json.RawMessage(data)}
+	if !json.Valid(data) {
+		frame.Data, _ = json.Marshal(string(data))
+		frame.Text = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(frame); err != nil {
+		return messageType, data, fmt.Errorf("写入录制文件失败: %w", err)
+	}
+	return messageType, data, nil
+}
+
+// Close 关闭被包装的帧来源
+func (r *ChatRecorder) Close() error {
+	return r.src.Close()
+}
+
+// ChatReplayer 从录制文件回放帧，按原始时间间隔（可加速）返回，结束时返回 io.EOF
+type ChatReplayer struct {
+	scanner *bufio.Scanner
+	speed   float64
+	last    time.Time
+	closed  chan struct{}
+	once    sync.Once
+}
+
+// NewChatReplayer 创建回放器，speed 为回放倍速，1 为实时，小于等于 0 表示不等待立即回放
+func NewChatReplayer(r io.Reader, speed float64) *ChatReplayer {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &ChatReplayer{
+		scanner: scanner,
+		speed:   speed,
+		closed:  make(chan struct{}),
+	}
+}
+
+// ReadMessage 返回下一帧，按录制时的时间间隔等待
+func (r *ChatReplayer) ReadMessage() (int, []byte, error) {
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var frame RecordedFrame
+		if err := json.Unmarshal(line, &frame); err != nil {
+			return 0, nil, fmt.Errorf("解析录制文件失败: %w", err)
+		}
+
+		if err := r.wait(frame.Time); err != nil {
+			return 0, nil, err
+		}
+
+		data := []byte(frame.Data)
+		if frame.Text {
+			var text string
+			if err := json.Unmarshal(frame.Data, &text); err != nil {
+				return 0, nil, fmt.Errorf("解析录制文件失败: %w", err)
+			}
+			data = []byte(text)
+		}
+		return websocket.TextFrame, data, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return 0, nil, fmt.Errorf("读取录制文件失败: %w", err)
+	}
+	return 0, nil, io.EOF
+}
+
+// wait 按倍速等待到下一帧的时间点
+func (r *ChatReplayer) wait(t time.Time) error {
+	defer func() { r.last = t }()
+	if r.speed <= 0 || r.last.IsZero() {
+		return r.checkClosed()
+	}
+
+	delay := time.Duration(float64(t.Sub(r.last)) / r.speed)
+	if delay <= 0 {
+		return r.checkClosed()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-r.closed:
+		return io.ErrClosedPipe
+	}
+}
+
+func (r *ChatReplayer) checkClosed() error {
+	select {
+	case <-r.closed:
+		return io.ErrClosedPipe
+	default:
+		return nil
+	}
+}
+
+// FrameTime 返回最近一帧的录制时间
+func (r *ChatReplayer) FrameTime() time.Time {
+	return r.last
+}
+
+// Close 停止回放
+func (r *ChatReplayer) Close() error {
+	r.once.Do(func() { close(r.closed) })
+	return nil
+}
@@ -0,0 +1,123 @@
+package fishpi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChatRecorderReplayRoundTrip(t *testing.T) {
+	frames := []string{
+		`{"type":"msg","oId":"1"}`,
+		`pong`,          // 非 JSON 帧
+		`"json string"`, // JSON 字符串字面量，回放时不能被解包
+		`{"type":"online"}`,
+	}
+
+	var buf bytes.Buffer
+	rec := NewChatRecorder(newFakeFrameSource(frames...), &buf)
+	for range frames {
+		if _, _, err := rec.ReadMessage(); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	if _, _, err := rec.ReadMessage(); err != io.EOF {
+		t.Fatalf("record end = %v, want io.EOF", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(frames) {
+		t.Fatalf("recorded %d lines, want %d", len(lines), len(frames))
+	}
+	var pong RecordedFrame
+	if err := json.Unmarshal([]byte(lines[1]), &pong); err != nil || !pong.Text || string(pong.Data) != `"pong"` {
+		t.Errorf("non-JSON frame recorded as %s", lines[1])
+	}
+
+	rp := NewChatReplayer(&buf, 0)
+	for i, want := range frames {
+		_, data, err := rp.ReadMessage()
+		if err != nil {
+			t.Fatalf("replay frame %d: %v", i, err)
+		}
+		if string(data) != want {
+			t.Errorf("frame %d = %s, want %s", i, data, want)
+		}
+		if rp.FrameTime().IsZero() {
+			t.Errorf("frame %d has no recorded time", i)
+		}
+	}
+	if _, _, err := rp.ReadMessage(); err != io.EOF {
+		t.Errorf("replay end = %v, want io.EOF", err)
+	}
+}
+
+// recording 构造录制文件，帧之间间隔 gap
+func recording(t *testing.T, gap time.Duration, n int) io.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		if err := enc.Encode(RecordedFrame{Time: start.Add(time.Duration(i) * gap), Data: json.RawMessage(`{"type":"msg"}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &buf
+}
+
+func TestChatReplayerSpeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		gap     time.Duration
+		speed   float64
+		minTime time.Duration
+		maxTime time.Duration
+	}{
+		{name: "speed 为 0 不等待", gap: time.Hour, speed: 0, maxTime: time.Second},
+		{name: "speed 为负数不等待", gap: time.Hour, speed: -1, maxTime: time.Second},
+		{name: "按倍速等待", gap: 200 * time.Millisecond, speed: 10, minTime: 20 * time.Millisecond, maxTime: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := NewChatReplayer(recording(t, tt.gap, 3), tt.speed)
+			start := time.Now()
+			for i := 0; i < 3; i++ {
+				if _, _, err := rp.ReadMessage(); err != nil {
+					t.Fatalf("frame %d: %v", i, err)
+				}
+			}
+			elapsed := time.Since(start)
+			if elapsed < tt.minTime || elapsed > tt.maxTime {
+				t.Errorf("elapsed %s, want between %s and %s", elapsed, tt.minTime, tt.maxTime)
+			}
+		})
+	}
+}
+
+func TestChatReplayerCloseInterruptsWait(t *testing.T) {
+	rp := NewChatReplayer(recording(t, time.Hour, 2), 1)
+	if _, _, err := rp.ReadMessage(); err != nil {
+		t.Fatal(err)
+	}
+
+	time.AfterFunc(10*time.Millisecond, func() { rp.Close() })
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := rp.ReadMessage()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != io.ErrClosedPipe {
+			t.Errorf("ReadMessage after Close = %v, want io.ErrClosedPipe", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not interrupt wait")
+	}
+}
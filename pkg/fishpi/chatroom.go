@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"dpbug/fishpi/go-client/pkg/fishpi/models"
 	"dpbug/fishpi/go-client/pkg/fishpi/websocket"
@@ -93,20 +95,72 @@ func (c *Client) OpenRedPacket(oId string, gesture int) (*models.RedPacketInfo,
 		return nil, fmt.Errorf("领取红包失败: %s", result.Msg)
 	}
 
-	c.Logger.Info("领取红包成功", zap.Int("points", result.Data.Money))
+	// Data.Money 是红包总额，我的积分变动需要从领取名单中查找
+	username, err := c.currentUsername()
+	if err != nil {
+		c.Logger.Warn("获取当前用户失败，红包未记账", zap.String("oId", oId), zap.Error(err))
+		return &result, nil
+	}
+	amount, ok := result.AmountOf(username)
+
+	c.Logger.Info("领取红包成功", zap.Int("points", amount), zap.Bool("in_who", ok))
+
+	// 猜拳红包输掉时积分为负，同样记账
+	if amount != 0 {
+		c.recordLedger(LedgerEntry{
+			Kind:         LedgerRedPacketIn,
+			Counterparty: result.Data.UserName,
+			Amount:       amount,
+			Memo:         result.Data.Msg,
+			RefID:        oId,
+		})
+	}
 	return &result, nil
 }
 
-// 连接聊天室 WebSocket
-// 需要先请求接口拿到websocket节点信息，随后再根据返回的节点信息去连接websocket
-func (c *Client) ConnectChatRoom(ctx context.Context, opts ...websocket.ChatRoomConnOption) (*websocket.ChatRoomConn, error) {
+// GetChatMessage 获取指定的聊天室消息，红包消息的内容包含当前的领取情况
+func (c *Client) GetChatMessage(oId string) (*models.ChatMessage, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("API Key未设置，请先登录")
+	}
+	if oId == "" {
+		return nil, fmt.Errorf("消息ID不能为空")
+	}
+
+	c.Logger.Info("获取聊天室消息", zap.String("oId", oId))
+
+	// mode=0 返回该消息及其上下文
+	path := fmt.Sprintf("/chat-room/getMessage?oId=%s&mode=0&size=1&type=html", url.QueryEscape(oId))
+	resp, err := c.doRequest(http.MethodGet, path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var history models.ChatHistory
+	if err := c.parseResponse(resp, &history); err != nil {
+		return nil, err
+	}
+
+	if history.Code != 0 {
+		return nil, fmt.Errorf("获取聊天室消息失败: %s", history.Msg)
+	}
+
+	for i := range history.Data {
+		if history.Data[i].OID == oId {
+			return &history.Data[i], nil
+		}
+	}
+	return nil, fmt.Errorf("未找到聊天室消息: %s", oId)
+}
+
+// GetChatRoomNodes 获取聊天室节点信息（自动分配的节点及全部可选节点）
+func (c *Client) GetChatRoomNodes() (*models.ChatRoomNode, error) {
 	if c.APIKey == "" {
 		return nil, fmt.Errorf("API Key未设置，请先登录")
 	}
 
 	c.Logger.Info("获取聊天室节点信息")
 
-	// 先请求接口拿到websocket节点信息
 	resp, err := c.doRequest(http.MethodGet, "/chat-room/node/get", nil, true)
 	if err != nil {
 		return nil, err
@@ -123,8 +177,67 @@ func (c *Client) ConnectChatRoom(ctx context.Context, opts ...websocket.ChatRoom
 		return nil, fmt.Errorf("获取聊天室节点信息失败: %s", nodeResp.Msg)
 	}
 
-	c.Logger.Info("获取到节点信息", zap.String("node", nodeResp.Data))
+	c.Logger.Info("获取到节点信息",
+		zap.String("node", nodeResp.Data),
+		zap.Int("available", len(nodeResp.Avaliable)),
+	)
+
+	return &nodeResp, nil
+}
+
+// ProbeChatRoomNodes 探测所有可选节点的握手延迟，结果按延迟从低到高排序
+func (c *Client) ProbeChatRoomNodes(ctx context.Context) ([]websocket.NodeProbe, error) {
+	nodeResp, err := c.GetChatRoomNodes()
+	if err != nil {
+		return nil, err
+	}
+	return websocket.ProbeNodes(ctx, toNodeList(nodeResp), c.UserAgent), nil
+}
+
+// 连接聊天室 WebSocket
+// 需要先请求接口拿到websocket节点信息，随后再根据返回的节点信息去连接websocket
+// 默认使用服务端自动分配的节点，可通过 websocket.WithChatRoomNode、WithChatRoomFastestNode、
+// WithChatRoomLeastLoadedNode 选项指定节点。
+func (c *Client) ConnectChatRoom(ctx context.Context, opts ...websocket.ChatRoomConnOption) (*websocket.ChatRoomConn, error) {
+	// 先请求接口拿到websocket节点信息
+	nodeResp, err := c.GetChatRoomNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	return websocket.ConnectChatRoomNode(ctx, toNodeList(nodeResp), c.UserAgent, c.Logger, opts...)
+}
+
+// toNodeList 转换节点信息
+func toNodeList(nodeResp *models.ChatRoomNode) websocket.NodeList {
+	list := websocket.NodeList{
+		DefaultURL: nodeResp.Data,
+		APIKey:     nodeResp.ApiKey,
+	}
+	for _, n := range nodeResp.Avaliable {
+		list.Nodes = append(list.Nodes, websocket.Node{
+			Name:   n.Name,
+			URL:    n.Node,
+			Online: n.Online,
+			Weight: n.Weight,
+		})
+	}
+	return list
+}
 
-	// 使用返回的 url 直接连接
-	return websocket.ConnectChatRoom(ctx, nodeResp.Data, c.UserAgent, c.Logger, opts...)
+// SetDiscussTopic 修改聊天室当前话题，修改成功后聊天室会推送 discussChanged 消息
+func (c *Client) SetDiscussTopic(topic string) error {
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return fmt.Errorf("话题不能为空")
+	}
+
+	c.Logger.Info("修改聊天室话题", zap.String("topic", topic))
+
+	if err := c.SendChatMessage("[setdiscuss]" + topic + "[/setdiscuss]"); err != nil {
+		return fmt.Errorf("修改话题失败: %w", err)
+	}
+
+	c.Logger.Info("修改聊天室话题成功")
+	return nil
 }
@@ -11,6 +11,8 @@ import (
 	"sync"
 	"time"
 
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
 	"go.uber.org/zap"
 )
 
@@ -32,10 +34,13 @@ type Client struct {
 	UserAgent     string
 	ClientName    string
 	APIKey        string
+	MFASecret     string // TOTP 密钥（Base32），设置后 Login 可自动生成二重验证码
 	Logger        *zap.Logger
 	Silent        bool                 // 静默模式：不输出Info/Debug日志
+	Ledger        *Ledger              // 积分账本（可选），记录转账和红包收支
 	lastReqByPath map[string]time.Time // 记录每个接口端点的上次请求时间
-	mu            sync.Mutex           // 保护lastReqByPath的并发访问
+	currentUser   *models.User         // 最近一次 GetUser 获取到的当前用户
+	mu            sync.Mutex           // 保护lastReqByPath和currentUser的并发访问
 }
 
 // ClientOption 客户端配置选项
@@ -76,6 +81,20 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithMFASecret 设置 TOTP 密钥，用于无人值守登录时自动生成二重验证码
+func WithMFASecret(secret string) ClientOption {
+	return func(c *Client) {
+		c.MFASecret = secret
+	}
+}
+
+// WithLedger 设置积分账本，转账和领取红包时自动记账
+func WithLedger(ledger *Ledger) ClientOption {
+	return func(c *Client) {
+		c.Ledger = ledger
+	}
+}
+
 // WithSilent 设置静默模式（不输出Info/Debug日志）
 func WithSilent(silent bool) ClientOption {
 	return func(c *Client) {
@@ -113,19 +132,24 @@ type CommonResponse struct {
 }
 
 func (c *Client) doRequest(method, path string, body interface{}, needsAuth bool) (*http.Response, error) {
-	// 构建完整URL
-	url := c.BaseURL + path
-
 	// 序列化请求体
-	var reqBody io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("序列化请求体失败: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
+		return c.doRawRequest(method, path, bytes.NewBuffer(jsonData), "application/json", needsAuth)
 	}
 
+	return c.doRawRequest(method, path, nil, "", needsAuth)
+}
+
+// doRawRequest 发送任意格式请求体的请求（如 multipart 上传）
+// contentType 为空时不设置 Content-Type
+func (c *Client) doRawRequest(method, path string, reqBody io.Reader, contentType string, needsAuth bool) (*http.Response, error) {
+	// 构建完整URL
+	url := c.BaseURL + path
+
 	// 创建请求
 	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
@@ -134,8 +158,8 @@ func (c *Client) doRequest(method, path string, body interface{}, needsAuth bool
 
 	// 设置请求头
 	req.Header.Set("User-Agent", c.UserAgent)
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	// 如果需要认证，添加API Key到请求中
@@ -147,24 +171,7 @@ func (c *Client) doRequest(method, path string, body interface{}, needsAuth bool
 	}
 
 	// 请求频率控制 - 针对单个接口端点
-	c.mu.Lock()
-	lastReq, exists := c.lastReqByPath[path]
-	if exists {
-		elapsed := time.Since(lastReq)
-		if elapsed < MinRequestInterval*time.Second {
-			waitTime := MinRequestInterval*time.Second - elapsed
-			c.mu.Unlock()
-			if !c.Silent {
-				c.Logger.Info("等待接口请求间隔",
-					zap.String("path", path),
-					zap.Duration("wait_time", waitTime),
-				)
-			}
-			time.Sleep(waitTime)
-			c.mu.Lock()
-		}
-	}
-	c.mu.Unlock()
+	c.waitRateLimit(path)
 
 	// 记录请求
 	if !c.Silent {
@@ -191,6 +198,31 @@ func (c *Client) doRequest(method, path string, body interface{}, needsAuth bool
 	return resp, nil
 }
 
+// waitRateLimit 等待到距离同一接口上次请求满 MinRequestInterval 秒
+// 需要在请求前生成时效性内容（如二重验证码）时，可先调用该方法再构建请求体。
+func (c *Client) waitRateLimit(path string) {
+	c.mu.Lock()
+	lastReq, exists := c.lastReqByPath[path]
+	c.mu.Unlock()
+	if !exists {
+		return
+	}
+
+	elapsed := time.Since(lastReq)
+	if elapsed >= MinRequestInterval*time.Second {
+		return
+	}
+
+	waitTime := MinRequestInterval*time.Second - elapsed
+	if !c.Silent {
+		c.Logger.Info("等待接口请求间隔",
+			zap.String("path", path),
+			zap.Duration("wait_time", waitTime),
+		)
+	}
+	time.Sleep(waitTime)
+}
+
 func (c *Client) parseResponse(resp *http.Response, target interface{}) error {
 	defer resp.Body.Close()
 
@@ -225,14 +257,32 @@ func (c *Client) parseResponse(resp *http.Response, target interface{}) error {
 	return nil
 }
 
-// SetAPIKey 设置API Key
+// SetAPIKey 设置API Key，同时清除缓存的当前用户
 func (c *Client) SetAPIKey(apiKey string) {
+	c.mu.Lock()
 	c.APIKey = apiKey
+	c.currentUser = nil
+	c.mu.Unlock()
 	if !c.Silent {
 		c.Logger.Info("API Key已设置")
 	}
 }
 
+// currentUsername 获取当前登录用户名，优先使用缓存的用户信息
+func (c *Client) currentUsername() (string, error) {
+	c.mu.Lock()
+	user := c.currentUser
+	c.mu.Unlock()
+
+	if user == nil {
+		var err error
+		if user, err = c.GetUser(); err != nil {
+			return "", err
+		}
+	}
+	return user.UserName, nil
+}
+
 // GetAPIKey 获取当前API Key
 func (c *Client) GetAPIKey() string {
 	return c.APIKey
@@ -0,0 +1,57 @@
+package fishpi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
+	"go.uber.org/zap"
+)
+
+func TestCurrentUsernameAfterKeyChange(t *testing.T) {
+	// 不同的 API Key 对应不同的用户
+	users := map[string]string{"key-a": "alice", "key-b": "bob"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := users[r.URL.Query().Get("apiKey")]
+		if !ok {
+			json.NewEncoder(w).Encode(UserResponse{Code: -1, Msg: "invalid key"})
+			return
+		}
+		json.NewEncoder(w).Encode(UserResponse{Code: 0, Data: &models.User{UserName: name}})
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithLogger(zap.NewNop()), WithSilent(true))
+	// 测试中不需要等待接口请求间隔
+	clearRateLimit := func() {
+		c.mu.Lock()
+		c.lastReqByPath = make(map[string]time.Time)
+		c.mu.Unlock()
+	}
+
+	if _, err := c.LoginWithKey("key-a"); err != nil {
+		t.Fatal(err)
+	}
+	if name, err := c.currentUsername(); err != nil || name != "alice" {
+		t.Fatalf("currentUsername = %q, %v, want alice", name, err)
+	}
+
+	c.SetAPIKey("key-b")
+	clearRateLimit()
+	if name, err := c.currentUsername(); err != nil || name != "bob" {
+		t.Fatalf("after SetAPIKey: currentUsername = %q, %v, want bob", name, err)
+	}
+
+	clearRateLimit()
+	if _, err := c.LoginWithKey("bad"); err == nil {
+		t.Fatal("expected error for invalid key")
+	}
+	clearRateLimit()
+	if _, err := c.currentUsername(); err == nil {
+		t.Fatal("expected error after invalid login, got cached user")
+	}
+}
@@ -0,0 +1,121 @@
+package fishpi
+
+import (
+	"fmt"
+	"net/http"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
+	"go.uber.org/zap"
+)
+
+// CommentOption 评论选项
+type CommentOption func(*models.CommentRequest)
+
+// WithCommentAnonymous 匿名评论
+func WithCommentAnonymous() CommentOption {
+	return func(r *models.CommentRequest) {
+		r.CommentAnonymous = true
+	}
+}
+
+// WithCommentVisibleToAuthor 评论仅楼主可见
+func WithCommentVisibleToAuthor() CommentOption {
+	return func(r *models.CommentRequest) {
+		r.CommentVisible = true
+	}
+}
+
+// PostComment 评论帖子
+// articleID: 帖子 oId
+// content: 评论内容（支持 Markdown 格式）
+func (c *Client) PostComment(articleID, content string, opts ...CommentOption) error {
+	return c.postComment(models.CommentRequest{
+		ArticleID:      articleID,
+		CommentContent: content,
+	}, opts)
+}
+
+// ReplyComment 回复帖子下的某条评论
+// replyTo: 被回复的评论 oId
+func (c *Client) ReplyComment(articleID, replyTo, content string, opts ...CommentOption) error {
+	if replyTo == "" {
+		return fmt.Errorf("被回复的评论ID不能为空")
+	}
+	return c.postComment(models.CommentRequest{
+		ArticleID:                articleID,
+		CommentContent:           content,
+		CommentOriginalCommentID: replyTo,
+	}, opts)
+}
+
+func (c *Client) postComment(reqBody models.CommentRequest, opts []CommentOption) error {
+	for _, opt := range opts {
+		opt(&reqBody)
+	}
+
+	if c.APIKey == "" {
+		return fmt.Errorf("API Key未设置，请先登录")
+	}
+	if reqBody.ArticleID == "" {
+		return fmt.Errorf("帖子ID不能为空")
+	}
+	if reqBody.CommentContent == "" {
+		return fmt.Errorf("评论内容不能为空")
+	}
+
+	c.Logger.Info("发布评论",
+		zap.String("article_id", reqBody.ArticleID),
+		zap.String("reply_to", reqBody.CommentOriginalCommentID),
+		zap.Bool("anonymous", reqBody.CommentAnonymous),
+		zap.Bool("visible_to_author", reqBody.CommentVisible),
+		zap.Int("content_length", len(reqBody.CommentContent)),
+	)
+
+	reqBody.APIKey = c.APIKey
+
+	// apiKey 已经在请求体中
+	resp, err := c.doRequest(http.MethodPost, "/comment", reqBody, false)
+	if err != nil {
+		return err
+	}
+
+	var result models.ActionResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return err
+	}
+
+	if result.Code != 0 {
+		return fmt.Errorf("发布评论失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("发布评论成功")
+	return nil
+}
+
+// UpvoteComment 赞同评论，重复调用会取消赞同
+func (c *Client) UpvoteComment(id string) (*models.VoteResult, error) {
+	return c.vote("comment", id, true)
+}
+
+// DownvoteComment 反对评论，重复调用会取消反对
+func (c *Client) DownvoteComment(id string) (*models.VoteResult, error) {
+	return c.vote("comment", id, false)
+}
+
+// ThankComment 感谢评论
+// 感谢会扣除积分，但服务端不返回实际扣除的数额，因此这里不报告消耗积分。
+func (c *Client) ThankComment(id string) error {
+	if id == "" {
+		return fmt.Errorf("评论ID不能为空")
+	}
+
+	c.Logger.Info("感谢评论", zap.String("id", id))
+
+	if err := c.postAction("/comment/thank", map[string]interface{}{"commentId": id}); err != nil {
+		return fmt.Errorf("感谢评论失败: %w", err)
+	}
+
+	c.Logger.Info("感谢评论成功")
+	return nil
+}
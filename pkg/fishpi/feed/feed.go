@@ -0,0 +1,313 @@
+// Package feed 将清风明月转换为 Atom、RSS 2.0 和 JSON Feed 订阅源。
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+	"dpbug/fishpi/go-client/pkg/fishpi/render"
+)
+
+const (
+	// DefaultBaseURL 默认社区地址，用于生成条目链接
+	DefaultBaseURL = "https://fishpi.cn"
+	// titleMaxRunes 条目标题最大长度（由正文截取）
+	titleMaxRunes = 50
+)
+
+// Options 订阅源元信息
+type Options struct {
+	Title       string    // 订阅源标题
+	Description string    // 订阅源描述
+	BaseURL     string    // 社区地址，默认 DefaultBaseURL
+	SelfURL     string    // 订阅源自身地址（可选）
+	Updated     time.Time // 更新时间，为零值时取最新条目的时间
+}
+
+func (o Options) withDefaults(items []models.Breezemoon) Options {
+	if o.Title == "" {
+		o.Title = "摸鱼派 - 清风明月"
+	}
+	if o.Description == "" {
+		o.Description = "摸鱼派社区的清风明月"
+	}
+	if o.BaseURL == "" {
+		o.BaseURL = DefaultBaseURL
+	}
+	o.BaseURL = strings.TrimRight(o.BaseURL, "/")
+	if o.Updated.IsZero() {
+		for _, bm := range items {
+			if t := bm.UpdatedAt(); t.After(o.Updated) {
+				o.Updated = t
+			}
+		}
+	}
+	if o.Updated.IsZero() {
+		o.Updated = time.Now()
+	}
+	return o
+}
+
+// entry 各格式共用的条目信息
+type entry struct {
+	id      string
+	link    string
+	title   string
+	content string // HTML
+	author  string
+	avatar  string
+	city    string
+	created time.Time
+	updated time.Time
+}
+
+func toEntries(items []models.Breezemoon, opts Options) []entry {
+	entries := make([]entry, 0, len(items))
+	for _, bm := range items {
+		// 时间缺失时使用订阅源的更新时间，避免输出 0001-01-01
+		updated := bm.UpdatedAt()
+		if updated.IsZero() {
+			updated = opts.Updated
+		}
+		created := bm.CreatedAt()
+		if created.IsZero() {
+			created = updated
+		}
+		entries = append(entries, entry{
+			id:      opts.BaseURL + "/breezemoon/" + bm.OID,
+			link:    fmt.Sprintf("%s/member/%s/breezemoons/%s", opts.BaseURL, bm.BreezemoonAuthorName, bm.OID),
+			title:   entryTitle(bm),
+			content: bm.BreezemoonContent,
+			author:  bm.BreezemoonAuthorName,
+			avatar:  bm.BreezemoonAuthorThumbnailURL48,
+			city:    bm.BreezemoonCity,
+			created: created,
+			updated: updated,
+		})
+	}
+	return entries
+}
+
+// entryTitle 截取正文纯文本作为标题，带上城市信息
+func entryTitle(bm models.Breezemoon) string {
+	text := strings.Join(strings.Fields(render.ToPlain(bm.BreezemoonContent)), " ")
+	if utf8.RuneCountInString(text) > titleMaxRunes {
+		text = string([]rune(text)[:titleMaxRunes]) + "…"
+	}
+	if text == "" {
+		text = "[图片]"
+	}
+
+	title := bm.BreezemoonAuthorName + ": " + text
+	if bm.BreezemoonCity != "" {
+		title += " 📍" + bm.BreezemoonCity
+	}
+	return title
+}
+
+// Atom 生成 Atom 1.0 文档
+func Atom(items []models.Breezemoon, opts Options) ([]byte, error) {
+	opts = opts.withDefaults(items)
+
+	type atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+	}
+	type atomAuthor struct {
+		Name string `xml:"name"`
+		URI  string `xml:"uri,omitempty"`
+	}
+	type atomCategory struct {
+		Term string `xml:"term,attr"`
+	}
+	type atomContent struct {
+		Type string `xml:"type,attr"`
+		Body string `xml:",chardata"`
+	}
+	type atomEntry struct {
+		ID        string        `xml:"id"`
+		Title     string        `xml:"title"`
+		Link      atomLink      `xml:"link"`
+		Published string        `xml:"published"`
+		Updated   string        `xml:"updated"`
+		Author    atomAuthor    `xml:"author"`
+		Category  *atomCategory `xml:"category,omitempty"`
+		Content   atomContent   `xml:"content"`
+	}
+	type atomFeed struct {
+		XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+		ID       string      `xml:"id"`
+		Title    string      `xml:"title"`
+		Subtitle string      `xml:"subtitle"`
+		Updated  string      `xml:"updated"`
+		Links    []atomLink  `xml:"link"`
+		Entries  []atomEntry `xml:"entry"`
+	}
+
+	doc := atomFeed{
+		ID:       opts.BaseURL + "/breezemoons",
+		Title:    opts.Title,
+		Subtitle: opts.Description,
+		Updated:  opts.Updated.Format(time.RFC3339),
+		Links:    []atomLink{{Href: opts.BaseURL + "/breezemoons", Rel: "alternate"}},
+	}
+	if opts.SelfURL != "" {
+		doc.Links = append(doc.Links, atomLink{Href: opts.SelfURL, Rel: "self"})
+	}
+
+	for _, e := range toEntries(items, opts) {
+		ae := atomEntry{
+			ID:        e.id,
+			Title:     e.title,
+			Link:      atomLink{Href: e.link, Rel: "alternate"},
+			Published: e.created.Format(time.RFC3339),
+			Updated:   e.updated.Format(time.RFC3339),
+			Author:    atomAuthor{Name: e.author, URI: opts.BaseURL + "/member/" + e.author},
+			Content:   atomContent{Type: "html", Body: e.content},
+		}
+		if e.city != "" {
+			ae.Category = &atomCategory{Term: e.city}
+		}
+		doc.Entries = append(doc.Entries, ae)
+	}
+
+	return marshalXML(doc)
+}
+
+// RSS 生成 RSS 2.0 文档
+func RSS(items []models.Breezemoon, opts Options) ([]byte, error) {
+	opts = opts.withDefaults(items)
+
+	type rssGUID struct {
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	}
+	type rssItem struct {
+		Title       string  `xml:"title"`
+		Link        string  `xml:"link"`
+		GUID        rssGUID `xml:"guid"`
+		Description string  `xml:"description"`
+		Creator     string  `xml:"dc:creator"`
+		Category    string  `xml:"category,omitempty"`
+		PubDate     string  `xml:"pubDate"`
+	}
+	type rssChannel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		Description   string    `xml:"description"`
+		LastBuildDate string    `xml:"lastBuildDate"`
+		Items         []rssItem `xml:"item"`
+	}
+	type rssFeed struct {
+		XMLName xml.Name   `xml:"rss"`
+		Version string     `xml:"version,attr"`
+		DC      string     `xml:"xmlns:dc,attr"`
+		Channel rssChannel `xml:"channel"`
+	}
+
+	doc := rssFeed{
+		Version: "2.0",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         opts.Title,
+			Link:          opts.BaseURL + "/breezemoons",
+			Description:   opts.Description,
+			LastBuildDate: opts.Updated.Format(time.RFC1123Z),
+		},
+	}
+
+	for _, e := range toEntries(items, opts) {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       e.title,
+			Link:        e.link,
+			GUID:        rssGUID{IsPermaLink: false, Value: e.id},
+			Description: e.content,
+			Creator:     e.author,
+			Category:    e.city,
+			PubDate:     e.created.Format(time.RFC1123Z),
+		})
+	}
+
+	return marshalXML(doc)
+}
+
+// JSONFeed 生成 JSON Feed 1.1 文档
+func JSONFeed(items []models.Breezemoon, opts Options) ([]byte, error) {
+	opts = opts.withDefaults(items)
+
+	type jsonAuthor struct {
+		Name   string `json:"name"`
+		URL    string `json:"url,omitempty"`
+		Avatar string `json:"avatar,omitempty"`
+	}
+	type jsonItem struct {
+		ID            string       `json:"id"`
+		URL           string       `json:"url"`
+		Title         string       `json:"title"`
+		ContentHTML   string       `json:"content_html"`
+		DatePublished string       `json:"date_published"`
+		DateModified  string       `json:"date_modified,omitempty"`
+		Authors       []jsonAuthor `json:"authors"`
+		Tags          []string     `json:"tags,omitempty"`
+	}
+	type jsonFeed struct {
+		Version     string     `json:"version"`
+		Title       string     `json:"title"`
+		HomePageURL string     `json:"home_page_url"`
+		FeedURL     string     `json:"feed_url,omitempty"`
+		Description string     `json:"description"`
+		Items       []jsonItem `json:"items"`
+	}
+
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       opts.Title,
+		HomePageURL: opts.BaseURL + "/breezemoons",
+		FeedURL:     opts.SelfURL,
+		Description: opts.Description,
+		Items:       []jsonItem{},
+	}
+
+	for _, e := range toEntries(items, opts) {
+		item := jsonItem{
+			ID:            e.id,
+			URL:           e.link,
+			Title:         e.title,
+			ContentHTML:   e.content,
+			DatePublished: e.created.Format(time.RFC3339),
+			DateModified:  e.updated.Format(time.RFC3339),
+			Authors: []jsonAuthor{{
+				Name:   e.author,
+				URL:    opts.BaseURL + "/member/" + e.author,
+				Avatar: e.avatar,
+			}},
+		}
+		if e.city != "" {
+			item.Tags = []string{e.city}
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("生成订阅源失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("生成订阅源失败: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,248 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+)
+
+// testItems 覆盖毫秒时间戳、字符串时间和时间缺失三种情况
+func testItems() []models.Breezemoon {
+	return []models.Breezemoon{
+		{
+			OID:                  "1730000000000",
+			BreezemoonAuthorName: "alice",
+			BreezemoonContent:    `<p>a &amp; b <b>"粗体"</b></p>`,
+			BreezemoonCity:       "上海",
+			BreezemoonCreated:    1730000000000, // 2024-10-27 11:33:20 +08:00
+			BreezemoonUpdated:    1730000060000, // 2024-10-27 11:34:20 +08:00
+		},
+		{
+			OID:                  "1730001600000",
+			BreezemoonAuthorName: "bob",
+			BreezemoonContent:    `<p><img src="https://example.com/a.png"></p>`,
+			BreezemoonCreateTime: "2024-10-27 12:00:00",
+		},
+		{
+			OID:                  "3",
+			BreezemoonAuthorName: "carol",
+			BreezemoonContent:    "<p>没有时间</p>",
+		},
+	}
+}
+
+// wantEntry 期望的条目，时间为服务器时区下的 RFC3339 格式
+type wantEntry struct {
+	id, title, content, published, updated, category string
+}
+
+var wantEntries = []wantEntry{
+	{
+		id:        "https://fishpi.cn/breezemoon/1730000000000",
+		title:     `alice: a & b "粗体" 📍上海`,
+		content:   `<p>a &amp; b <b>"粗体"</b></p>`,
+		published: "2024-10-27T11:33:20+08:00",
+		updated:   "2024-10-27T11:34:20+08:00",
+		category:  "上海",
+	},
+	{
+		id:        "https://fishpi.cn/breezemoon/1730001600000",
+		title:     "bob: [图片] https://example.com/a.png",
+		content:   `<p><img src="https://example.com/a.png"></p>`,
+		published: "2024-10-27T12:00:00+08:00",
+		updated:   "2024-10-27T12:00:00+08:00",
+	},
+	{
+		// 时间缺失时使用订阅源的更新时间（最新条目的时间）
+		id:        "https://fishpi.cn/breezemoon/3",
+		title:     "carol: 没有时间",
+		content:   "<p>没有时间</p>",
+		published: "2024-10-27T12:00:00+08:00",
+		updated:   "2024-10-27T12:00:00+08:00",
+	},
+}
+
+const wantFeedUpdated = "2024-10-27T12:00:00+08:00"
+
+func TestAtom(t *testing.T) {
+	data, err := Atom(testItems(), Options{SelfURL: "https://example.com/atom.xml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// HTML 内容必须转义，不能作为 XML 元素输出
+	if strings.Contains(string(data), "<b>") {
+		t.Errorf("content is not escaped:\n%s", data)
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Title   string   `xml:"title"`
+		Updated string   `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Title     string `xml:"title"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+			Author    string `xml:"author>name"`
+			Category  struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+			Content struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid Atom: %v\n%s", err, data)
+	}
+
+	if doc.Title != "摸鱼派 - 清风明月" || doc.Updated != wantFeedUpdated {
+		t.Errorf("feed title/updated = %q, %q", doc.Title, doc.Updated)
+	}
+	if len(doc.Links) != 2 || doc.Links[1].Rel != "self" || doc.Links[1].Href != "https://example.com/atom.xml" {
+		t.Errorf("links = %+v", doc.Links)
+	}
+	if len(doc.Entries) != len(wantEntries) {
+		t.Fatalf("entries = %d, want %d", len(doc.Entries), len(wantEntries))
+	}
+	for i, want := range wantEntries {
+		e := doc.Entries[i]
+		if e.ID != want.id || e.Title != want.title || e.Content.Body != want.content || e.Content.Type != "html" {
+			t.Errorf("entry %d = %+v, want %+v", i, e, want)
+		}
+		if e.Published != want.published || e.Updated != want.updated {
+			t.Errorf("entry %d time = %s / %s, want %s / %s", i, e.Published, e.Updated, want.published, want.updated)
+		}
+		if e.Category.Term != want.category {
+			t.Errorf("entry %d category = %q, want %q", i, e.Category.Term, want.category)
+		}
+	}
+}
+
+func TestRSS(t *testing.T) {
+	data, err := RSS(testItems(), Options{BaseURL: "https://fishpi.cn/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "<b>") {
+		t.Errorf("description is not escaped:\n%s", data)
+	}
+
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Link          string `xml:"link"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title       string `xml:"title"`
+				Link        string `xml:"link"`
+				GUID        string `xml:"guid"`
+				Description string `xml:"description"`
+				Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				Category    string `xml:"category"`
+				PubDate     string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid RSS: %v\n%s", err, data)
+	}
+
+	if doc.Version != "2.0" || doc.Channel.Link != "https://fishpi.cn/breezemoons" {
+		t.Errorf("version/link = %q, %q", doc.Version, doc.Channel.Link)
+	}
+	if doc.Channel.LastBuildDate != "Sun, 27 Oct 2024 12:00:00 +0800" {
+		t.Errorf("lastBuildDate = %q", doc.Channel.LastBuildDate)
+	}
+	if len(doc.Channel.Items) != len(wantEntries) {
+		t.Fatalf("items = %d, want %d", len(doc.Channel.Items), len(wantEntries))
+	}
+	wantPubDates := []string{
+		"Sun, 27 Oct 2024 11:33:20 +0800",
+		"Sun, 27 Oct 2024 12:00:00 +0800",
+		"Sun, 27 Oct 2024 12:00:00 +0800",
+	}
+	for i, want := range wantEntries {
+		item := doc.Channel.Items[i]
+		if item.GUID != want.id || item.Title != want.title || item.Description != want.content || item.Category != want.category {
+			t.Errorf("item %d = %+v, want %+v", i, item, want)
+		}
+		if item.PubDate != wantPubDates[i] {
+			t.Errorf("item %d pubDate = %q, want %q", i, item.PubDate, wantPubDates[i])
+		}
+		if item.Creator == "" {
+			t.Errorf("item %d has no dc:creator", i)
+		}
+	}
+	if doc.Channel.Items[0].Link != "https://fishpi.cn/member/alice/breezemoons/1730000000000" {
+		t.Errorf("link = %q", doc.Channel.Items[0].Link)
+	}
+}
+
+func TestJSONFeed(t *testing.T) {
+	data, err := JSONFeed(testItems(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Version string `json:"version"`
+		Items   []struct {
+			ID            string   `json:"id"`
+			Title         string   `json:"title"`
+			ContentHTML   string   `json:"content_html"`
+			DatePublished string   `json:"date_published"`
+			DateModified  string   `json:"date_modified"`
+			Tags          []string `json:"tags"`
+			Authors       []struct {
+				Name string `json:"name"`
+			} `json:"authors"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON Feed: %v\n%s", err, data)
+	}
+
+	if doc.Version != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("version = %q", doc.Version)
+	}
+	if len(doc.Items) != len(wantEntries) {
+		t.Fatalf("items = %d, want %d", len(doc.Items), len(wantEntries))
+	}
+	for i, want := range wantEntries {
+		item := doc.Items[i]
+		if item.ID != want.id || item.Title != want.title || item.ContentHTML != want.content {
+			t.Errorf("item %d = %+v, want %+v", i, item, want)
+		}
+		if item.DatePublished != want.published || item.DateModified != want.updated {
+			t.Errorf("item %d time = %s / %s, want %s / %s", i, item.DatePublished, item.DateModified, want.published, want.updated)
+		}
+		if (want.category == "") != (len(item.Tags) == 0) {
+			t.Errorf("item %d tags = %v, want %q", i, item.Tags, want.category)
+		}
+		if len(item.Authors) != 1 || item.Authors[0].Name == "" {
+			t.Errorf("item %d authors = %+v", i, item.Authors)
+		}
+	}
+}
+
+func TestJSONFeedEmpty(t *testing.T) {
+	updated := time.Date(2024, 10, 27, 12, 0, 0, 0, models.ServerLocation)
+	data, err := JSONFeed(nil, Options{Updated: updated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 没有条目时 items 仍需输出为空数组
+	if !strings.Contains(string(data), `"items": []`) {
+		t.Errorf("empty feed should contain an empty items array:\n%s", data)
+	}
+}
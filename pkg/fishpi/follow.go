@@ -0,0 +1,225 @@
+package fishpi
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
+	"go.uber.org/zap"
+)
+
+// FollowUser 关注用户
+// username: 要关注的用户名（内部会查询其 oId）
+func (c *Client) FollowUser(username string) error {
+	return c.followUser("/follow/user", username, "关注用户")
+}
+
+// UnfollowUser 取消关注用户
+func (c *Client) UnfollowUser(username string) error {
+	return c.followUser("/unfollow/user", username, "取消关注用户")
+}
+
+func (c *Client) followUser(path, username, action string) error {
+	if c.APIKey == "" {
+		return fmt.Errorf("API Key未设置，请先登录")
+	}
+	if username == "" {
+		return fmt.Errorf("用户名不能为空")
+	}
+
+	// 关注接口需要用户 oId
+	member, err := c.GetMemberInfo(username)
+	if err != nil {
+		return err
+	}
+	if member.OID == "" {
+		return fmt.Errorf("用户不存在: %s", username)
+	}
+
+	c.Logger.Info(action, zap.String("username", username), zap.String("oId", member.OID))
+
+	if err := c.postAction(path, map[string]interface{}{"followingId": member.OID}); err != nil {
+		return fmt.Errorf("%s失败: %w", action, err)
+	}
+
+	c.Logger.Info(action+"成功", zap.String("username", username))
+	return nil
+}
+
+// ListFollowers 获取用户的粉丝列表
+// page: 页码（从1开始）
+func (c *Client) ListFollowers(username string, page int) (*models.FollowerList, error) {
+	if username == "" {
+		return nil, fmt.Errorf("用户名不能为空")
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	c.Logger.Info("获取粉丝列表", zap.String("username", username), zap.Int("page", page))
+
+	path := fmt.Sprintf("/api/user/%s/followers?p=%d", url.PathEscape(username), page)
+	resp, err := c.doRequest(http.MethodGet, path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.FollowerListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("获取粉丝列表失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("获取粉丝列表成功", zap.Int("count", len(result.Data.Users)))
+	return &result.Data, nil
+}
+
+// ListFollowing 获取用户关注的用户列表
+// page: 页码（从1开始）
+func (c *Client) ListFollowing(username string, page int) (*models.FollowingList, error) {
+	if username == "" {
+		return nil, fmt.Errorf("用户名不能为空")
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	c.Logger.Info("获取关注列表", zap.String("username", username), zap.Int("page", page))
+
+	path := fmt.Sprintf("/api/user/%s/following/users?p=%d", url.PathEscape(username), page)
+	resp, err := c.doRequest(http.MethodGet, path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.FollowingListResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("获取关注列表失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("获取关注列表成功", zap.Int("count", len(result.Data.Users)))
+	return &result.Data, nil
+}
+
+// FollowEdge 关注关系：Follower 关注了 Following
+type FollowEdge struct {
+	Follower  string
+	Following string
+}
+
+// FollowGraph 关注关系图
+type FollowGraph struct {
+	Edges []FollowEdge
+
+	seen map[FollowEdge]struct{} // 已添加的关系，用于去重
+}
+
+// Add 添加一条关注关系（自动去重）
+func (g *FollowGraph) Add(follower, following string) {
+	edge := FollowEdge{Follower: follower, Following: following}
+	if g.seen == nil {
+		// 兼容直接构造或修改过 Edges 的图
+		g.seen = make(map[FollowEdge]struct{}, len(g.Edges))
+		for _, e := range g.Edges {
+			g.seen[e] = struct{}{}
+		}
+	}
+	if _, ok := g.seen[edge]; ok {
+		return
+	}
+	g.seen[edge] = struct{}{}
+	g.Edges = append(g.Edges, edge)
+}
+
+// Users 返回图中出现的所有用户名（已排序）
+func (g *FollowGraph) Users() []string {
+	seen := make(map[string]bool)
+	for _, e := range g.Edges {
+		seen[e.Follower] = true
+		seen[e.Following] = true
+	}
+	users := make([]string, 0, len(seen))
+	for u := range seen {
+		users = append(users, u)
+	}
+	sort.Strings(users)
+	return users
+}
+
+// WriteCSV 以 CSV 格式导出，表头为 follower,following
+func (g *FollowGraph) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"follower", "following"}); err != nil {
+		return err
+	}
+	for _, e := range g.Edges {
+		if err := cw.Write([]string{e.Follower, e.Following}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteDOT 以 Graphviz DOT 格式导出有向图
+func (g *FollowGraph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph fishpi {\n")
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", e.Follower, e.Following)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// FetchFollowGraph 抓取用户的粉丝和关注列表，构建以该用户为中心的关注关系图
+// maxPages: 粉丝/关注列表各自最多抓取的页数，非正值表示抓取全部
+func (c *Client) FetchFollowGraph(username string, maxPages int) (*FollowGraph, error) {
+	graph := &FollowGraph{}
+
+	for page := 1; maxPages <= 0 || page <= maxPages; page++ {
+		list, err := c.ListFollowers(username, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range list.Users {
+			graph.Add(u.UserName, username)
+		}
+		if len(list.Users) == 0 || !list.Pagination.HasNext(page) {
+			break
+		}
+	}
+
+	for page := 1; maxPages <= 0 || page <= maxPages; page++ {
+		list, err := c.ListFollowing(username, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range list.Users {
+			graph.Add(username, u.UserName)
+		}
+		if len(list.Users) == 0 || !list.Pagination.HasNext(page) {
+			break
+		}
+	}
+
+	c.Logger.Info("构建关注关系图完成",
+		zap.String("username", username),
+		zap.Int("edges", len(graph.Edges)),
+	)
+	return graph, nil
+}
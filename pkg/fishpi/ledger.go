@@ -0,0 +1,131 @@
+package fishpi
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// 账本记录类型
+const (
+	LedgerTransferOut = "transfer_out" // 转出积分
+	LedgerRedPacketIn = "redpacket_in" // 领取红包收支（猜拳红包输掉时为负）
+)
+
+// LedgerEntry 账本中的一条积分流水
+type LedgerEntry struct {
+	Time         time.Time `json:"time"`
+	Kind         string    `json:"kind"`             // 记录类型，见 Ledger* 常量
+	Counterparty string    `json:"counterparty"`     // 对方用户名（红包为发送者）
+	Amount       int       `json:"amount"`           // 积分变动，收入为正、支出为负
+	Memo         string    `json:"memo,omitempty"`   // 备注/祝福语
+	RefID        string    `json:"ref_id,omitempty"` // 关联 ID（如红包消息 oId）
+}
+
+// Ledger 本地只追加账本，以 JSONL 格式保存积分流水，便于事后对账
+type Ledger struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLedger 创建账本，path 为 JSONL 文件路径（不存在时在首次写入时创建）
+func NewLedger(path string) *Ledger {
+	return &Ledger{path: path}
+}
+
+// Path 返回账本文件路径
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// Append 追加一条流水，未设置时间时使用当前时间
+func (l *Ledger) Append(entry LedgerEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化账本记录失败: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("打开账本文件失败: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("写入账本失败: %w", err)
+	}
+	return nil
+}
+
+// Entries 读取账本中的全部流水，账本不存在时返回空列表
+func (l *Ledger) Entries() ([]LedgerEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打开账本文件失败: %w", err)
+	}
+	defer f.Close()
+
+	var entries []LedgerEntry
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry LedgerEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("账本第 %d 行解析失败: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取账本失败: %w", err)
+	}
+	return entries, nil
+}
+
+// LedgerSummary 账本汇总
+type LedgerSummary struct {
+	Income  int            // 总收入
+	Outgo   int            // 总支出（正数）
+	ByKind  map[string]int // 按记录类型汇总的积分变动
+	Entries int            // 流水条数
+}
+
+// Summarize 汇总 since 之后（含）的流水，since 为零值时汇总全部
+func (l *Ledger) Summarize(since time.Time) (*LedgerSummary, error) {
+	entries, err := l.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &LedgerSummary{ByKind: make(map[string]int)}
+	for _, e := range entries {
+		if !since.IsZero() && e.Time.Before(since) {
+			continue
+		}
+		summary.Entries++
+		summary.ByKind[e.Kind] += e.Amount
+		if e.Amount >= 0 {
+			summary.Income += e.Amount
+		} else {
+			summary.Outgo -= e.Amount
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,227 @@
+package models
+
+// ArticleAuthor 帖子/评论作者信息
+type ArticleAuthor struct {
+	OID                string `json:"oId"`
+	UserName           string `json:"userName"`
+	UserNickname       string `json:"userNickname"`
+	UserAvatarURL      string `json:"userAvatarURL"`
+	UserOnlineFlag     bool   `json:"userOnlineFlag"`
+	UserIntro          string `json:"userIntro,omitempty"`
+	UserURL            string `json:"userURL,omitempty"`
+	UserCity           string `json:"userCity,omitempty"`
+	UserPoint          int    `json:"userPoint,omitempty"`
+	SysMetal           string `json:"sysMetal,omitempty"`
+	FollowingUserCount int    `json:"followingUserCount,omitempty"`
+	FollowerCount      int    `json:"followerCount,omitempty"`
+}
+
+// ArticleTag 帖子标签
+type ArticleTag struct {
+	OID               string `json:"oId"`
+	TagTitle          string `json:"tagTitle"`
+	TagURI            string `json:"tagURI"`
+	TagIconPath       string `json:"tagIconPath,omitempty"`
+	TagDescription    string `json:"tagDescription,omitempty"`
+	TagReferenceCount int    `json:"tagReferenceCount,omitempty"`
+}
+
+// Article 帖子
+type Article struct {
+	OID                    string        `json:"oId"`
+	ArticleTitle           string        `json:"articleTitle"`
+	ArticleTitleEmoj       string        `json:"articleTitleEmoj,omitempty"` // 渲染过表情的标题（HTML）
+	ArticleAuthorID        string        `json:"articleAuthorId"`
+	ArticleAuthor          ArticleAuthor `json:"articleAuthor"`
+	ArticleTags            string        `json:"articleTags"` // 逗号分隔的标签
+	ArticleTagObjs         []ArticleTag  `json:"articleTagObjs,omitempty"`
+	ArticleType            int           `json:"articleType"` // 0=普通, 1=机要, 2=同城广播, 3=思绪, 5=问答
+	ArticlePermalink       string        `json:"articlePermalink"`
+	ArticlePreviewContent  string        `json:"articlePreviewContent,omitempty"` // 预览文本（列表接口返回）
+	ArticleContent         string        `json:"articleContent,omitempty"`        // 正文 HTML（详情接口返回）
+	ArticleOriginalContent string        `json:"articleOriginalContent,omitempty"`
+	ArticleCreateTimeStr   string        `json:"articleCreateTimeStr"` // 时间（字符串格式："2025-10-29 10:49:55"）
+	ArticleUpdateTimeStr   string        `json:"articleUpdateTimeStr,omitempty"`
+	TimeAgo                string        `json:"timeAgo"`
+	ArticleViewCount       int           `json:"articleViewCount"`
+	ArticleCommentCount    int           `json:"articleCommentCount"`
+	ArticleGoodCnt         int           `json:"articleGoodCnt"`
+	ArticleBadCnt          int           `json:"articleBadCnt"`
+	ArticleCollectCnt      int           `json:"articleCollectCnt"`
+	ArticleWatchCnt        int           `json:"articleWatchCnt"`
+	ArticleThankCnt        int           `json:"articleThankCnt"`
+	ArticleHeat            int           `json:"articleHeat"`
+	ArticlePerfect         int           `json:"articlePerfect"` // 1=精选
+	ArticleStick           int64         `json:"articleStick"`   // 置顶截止时间戳，0=未置顶
+	ArticleAnonymous       int           `json:"articleAnonymous"`
+	ArticleRewardPoint     int           `json:"articleRewardPoint"`             // 打赏积分
+	ArticleRewardContent   string        `json:"articleRewardContent,omitempty"` // 打赏后可见内容
+	ArticleVote            int           `json:"articleVote"`                    // 当前用户投票状态：-1=未投票, 0=赞同, 1=反对
+	ArticleComments        []Comment     `json:"articleComments,omitempty"`      // 评论列表（详情接口返回）
+	ArticleNiceComments    []Comment     `json:"articleNiceComments,omitempty"`  // 精选评论（详情接口返回）
+}
+
+// Comment 帖子评论
+type Comment struct {
+	OID                      string        `json:"oId"`
+	CommentOnArticleID       string        `json:"commentOnArticleId"`
+	CommentAuthorID          string        `json:"commentAuthorId"`
+	CommentAuthorName        string        `json:"commentAuthorName"`
+	CommentAuthorNickName    string        `json:"commentAuthorNickName,omitempty"`
+	CommentAuthorThumbnail   string        `json:"commentAuthorThumbnailURL,omitempty"`
+	Commenter                ArticleAuthor `json:"commenter"`
+	CommentContent           string        `json:"commentContent"` // 评论内容 HTML
+	CommentCreateTimeStr     string        `json:"commentCreateTimeStr"`
+	TimeAgo                  string        `json:"timeAgo"`
+	CommentOriginalCommentID string        `json:"commentOriginalCommentId,omitempty"` // 回复的评论 ID
+	CommentReplyCnt          int           `json:"commentReplyCnt"`
+	CommentGoodCnt           int           `json:"commentGoodCnt"`
+	CommentBadCnt            int           `json:"commentBadCnt"`
+	CommentThankCnt          int           `json:"commentThankCnt"`
+	CommentNice              bool          `json:"commentNice"`
+	CommentVote              int           `json:"commentVote"` // 当前用户投票状态：-1=未投票, 0=赞同, 1=反对
+	CommentAnonymous         int           `json:"commentAnonymous"`
+}
+
+// Pagination 分页信息
+type Pagination struct {
+	PaginationPageCount int   `json:"paginationPageCount"` // 总页数
+	PaginationPageNums  []int `json:"paginationPageNums"`  // 可跳转的页码
+}
+
+// HasNext 判断指定页之后是否还有数据
+func (p *Pagination) HasNext(page int) bool {
+	return page < p.PaginationPageCount
+}
+
+// ArticleList 帖子列表及分页信息
+type ArticleList struct {
+	Articles   []Article  `json:"articles"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// ArticleListResponse 帖子列表响应
+type ArticleListResponse struct {
+	Code int         `json:"code"`
+	Msg  string      `json:"msg,omitempty"`
+	Data ArticleList `json:"data"`
+}
+
+// ArticleDetail 帖子详情（含评论分页信息）
+type ArticleDetail struct {
+	Article    Article    `json:"article"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// ArticleDetailResponse 帖子详情响应
+type ArticleDetailResponse struct {
+	Code int           `json:"code"`
+	Msg  string        `json:"msg,omitempty"`
+	Data ArticleDetail `json:"data"`
+}
+
+// 帖子类型
+const (
+	ArticleTypeNormal    = 0 // 普通帖子
+	ArticleTypeSecret    = 1 // 机要
+	ArticleTypeBroadcast = 2 // 同城广播
+	ArticleTypeThought   = 3 // 思绪
+	ArticleTypeQnA       = 5 // 问答
+)
+
+// ArticleDraft 待发布/更新的帖子内容
+type ArticleDraft struct {
+	Title           string   // 标题
+	Content         string   // 正文（Markdown 格式）
+	Tags            []string // 标签，至少一个
+	Type            int      // 帖子类型，见 ArticleType* 常量
+	RewardContent   string   // 打赏后可见内容（Markdown 格式）
+	RewardPoint     int      // 打赏所需积分，0 表示不开启打赏
+	QnAOfferPoint   int      // 问答悬赏积分（仅问答帖）
+	Anonymous       bool     // 是否匿名发布
+	Commentable     bool     // 是否允许评论
+	NotifyFollowers bool     // 是否通知关注者
+	Hidden          bool     // 是否不在列表中展示
+}
+
+// NewArticleDraft 创建默认允许评论的普通帖子草稿
+func NewArticleDraft(title, content string, tags ...string) *ArticleDraft {
+	return &ArticleDraft{
+		Title:       title,
+		Content:     content,
+		Tags:        tags,
+		Type:        ArticleTypeNormal,
+		Commentable: true,
+	}
+}
+
+// ArticleRequest 发布/更新帖子请求
+type ArticleRequest struct {
+	APIKey                 string `json:"apiKey"`
+	ArticleTitle           string `json:"articleTitle"`
+	ArticleContent         string `json:"articleContent"`
+	ArticleTags            string `json:"articleTags"`
+	ArticleCommentable     bool   `json:"articleCommentable"`
+	ArticleNotifyFollowers bool   `json:"articleNotifyFollowers"`
+	ArticleType            int    `json:"articleType"`
+	ArticleShowInList      int    `json:"articleShowInList"` // 1=在列表中展示, 0=不展示
+	ArticleRewardContent   string `json:"articleRewardContent,omitempty"`
+	ArticleRewardPoint     int    `json:"articleRewardPoint,omitempty"`
+	ArticleQnAOfferPoint   int    `json:"articleQnAOfferPoint,omitempty"`
+	ArticleAnonymous       bool   `json:"articleAnonymous"`
+}
+
+// ArticlePostResponse 发布帖子响应
+type ArticlePostResponse struct {
+	Code      int    `json:"code"`
+	Msg       string `json:"msg,omitempty"`
+	ArticleID string `json:"article_id,omitempty"`
+}
+
+// ArticleUpdateResponse 更新帖子响应
+type ArticleUpdateResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg,omitempty"`
+}
+
+// CommentRequest 发布评论请求
+type CommentRequest struct {
+	APIKey                   string `json:"apiKey"`
+	ArticleID                string `json:"articleId"`
+	CommentContent           string `json:"commentContent"` // 评论内容（Markdown 格式）
+	CommentOriginalCommentID string `json:"commentOriginalCommentId,omitempty"`
+	CommentAnonymous         bool   `json:"commentAnonymous"`
+	CommentVisible           bool   `json:"commentVisible"` // true=仅楼主可见
+}
+
+// ActionResponse 互动操作（评论、感谢、关注、打赏等）的通用响应
+type ActionResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg,omitempty"`
+}
+
+// RewardResponse 打赏帖子响应
+type RewardResponse struct {
+	Code                 int    `json:"code"`
+	Msg                  string `json:"msg,omitempty"`
+	ArticleRewardContent string `json:"articleRewardContent,omitempty"` // 打赏后可见内容
+}
+
+// VoteResponse 投票响应
+type VoteResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg,omitempty"`
+	Type int    `json:"type"` // -1=取消投票, 0=投票成功
+}
+
+// VoteResult 投票结果
+type VoteResult struct {
+	Up        bool // true=赞同, false=反对
+	Cancelled bool // 重复投票时服务端会取消之前的投票
+}
+
+// PointsResult 需要消耗积分的操作结果
+type PointsResult struct {
+	PointsSpent int    // 本次操作消耗的积分
+	Content     string // 操作获得的内容（如打赏后可见的内容）
+}
@@ -3,22 +3,38 @@ package models
 import (
 	"encoding/json"
 	"strings"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/render"
 )
 
 // ChatRoomNode 聊天室节点信息
 type ChatRoomNode struct {
-	Code      int    `json:"code"` // 显示用户当前所在的区服中文名称
-	Msg       string `json:"msg,omitempty"`
-	Data      string `json:"data,omitempty"` // 自动分配的 WebSocket 地址，请取用该地址连接到聊天室频道
-	Avaliable []struct {
-		Node   string `json:"node"`   // 节点 WSS 地址
-		Name   string `json:"name"`   // 节点中文名称
-		Online int    `json:"online"` // 节点当前在线人数
-		Weight int    `json:"weight"` // 节点权重
-	} `json:"avaliable,omitempty"`
-	ApiKey string `json:"apiKey"` // 自动生成的 ApiKey，用于在手动选择节点时和 avaliable 中的 node 拼接，以连接到自定义节点的WebSocket服务器
+	Code      int                `json:"code"` // 显示用户当前所在的区服中文名称
+	Msg       string             `json:"msg,omitempty"`
+	Data      string             `json:"data,omitempty"`      // 自动分配的 WebSocket 地址，请取用该地址连接到聊天室频道
+	Avaliable []ChatRoomNodeInfo `json:"avaliable,omitempty"` // 可选节点列表
+	ApiKey    string             `json:"apiKey"`              // 自动生成的 ApiKey，用于在手动选择节点时和 avaliable 中的 node 拼接，以连接到自定义节点的WebSocket服务器
+}
+
+// ChatRoomNodeInfo 可选的聊天室节点
+type ChatRoomNodeInfo struct {
+	Node   string `json:"node"`   // 节点 WSS 地址
+	Name   string `json:"name"`   // 节点中文名称
+	Online int    `json:"online"` // 节点当前在线人数
+	Weight int    `json:"weight"` // 节点权重
 }
 
+// 聊天室 WebSocket 消息类型
+const (
+	ChatTypeMessage         = "msg"             // 聊天消息（包括红包消息）
+	ChatTypeRedPacketStatus = "redPacketStatus" // 红包领取状态
+	ChatTypeBarrage         = "barrager"        // 弹幕
+	ChatTypeDiscussChanged  = "discussChanged"  // 话题变更
+	ChatTypeOnline          = "online"          // 在线用户（连接后及有人进出时推送，包含当前话题）
+	ChatTypeCustomMessage   = "customMessage"   // 自定义进出聊天室提示
+	ChatTypeRevoke          = "revoke"          // 消息撤回
+)
+
 // ChatMessage 聊天室消息
 type ChatMessage struct {
 	OID              string `json:"oId"`
@@ -41,20 +57,23 @@ type ChatMessage struct {
 
 // RedPacketContent 红包消息内容（存在 Content 字段中）
 type RedPacketContent struct {
-	MsgType  string `json:"msgType"`  // 固定为 "redPacket"
-	Msg      string `json:"msg"`      // 红包祝福语
-	SenderId string `json:"senderId"` // 发送者ID
-	Recivers string `json:"recivers"` // 接收者列表（专属红包），API返回的是JSON字符串，如 "[]" 或 "[\"user1\"]"
-	Money    int    `json:"money"`    // 红包总金额（积分）
-	Count    int    `json:"count"`    // 红包数量
-	Type     string `json:"type"`     // 红包类型: random, average, specify, heartbeat, rockPaperScissors
-	Got      int    `json:"got"`      // 已领取数量
-	Who      []struct {
-		UserName  string `json:"userName"`
-		Avatar    string `json:"avatar"`
-		UserMoney int    `json:"userMoney"`
-		Time      string `json:"time"`
-	} `json:"who"` // 已领取者信息
+	MsgType  string              `json:"msgType"`  // 固定为 "redPacket"
+	Msg      string              `json:"msg"`      // 红包祝福语
+	SenderId string              `json:"senderId"` // 发送者ID
+	Recivers string              `json:"recivers"` // 接收者列表（专属红包），API返回的是JSON字符串，如 "[]" 或 "[\"user1\"]"
+	Money    int                 `json:"money"`    // 红包总金额（积分）
+	Count    int                 `json:"count"`    // 红包数量
+	Type     string              `json:"type"`     // 红包类型: random, average, specify, heartbeat, rockPaperScissors
+	Got      int                 `json:"got"`      // 已领取数量
+	Who      []RedPacketReceiver `json:"who"`      // 已领取者信息
+}
+
+// RedPacketReceiver 红包领取记录
+type RedPacketReceiver struct {
+	UserName  string `json:"userName"`
+	Avatar    string `json:"avatar"`
+	UserMoney int    `json:"userMoney"` // 领取到的积分
+	Time      string `json:"time"`      // 领取时间（字符串格式："2025-10-29 10:49:55"）
 }
 
 // GetReciverList 解析接收者列表（将JSON字符串转为数组）
@@ -72,7 +91,7 @@ func (rp *RedPacketContent) GetReciverList() ([]string, error) {
 // IsRedPacket 判断消息是否为红包消息
 func (m *ChatMessage) IsRedPacket() bool {
 	// 红包消息的 Content 是 JSON 格式，且以 { 开头
-	return strings.HasPrefix(strings.TrimSpace(m.Content), "{")
+	return m != nil && strings.HasPrefix(strings.TrimSpace(m.Content), "{")
 }
 
 // GetRedPacket 解析红包内容
@@ -93,8 +112,11 @@ func (m *ChatMessage) FormatDisplay() string {
 		}
 		return m.UserNickname + ": [红包] " + rp.Msg + " (ID: " + m.OID + ")"
 	}
-	// 移除 MD 中的 emoji 和特殊字符，保留纯文本
-	content := strings.ReplaceAll(m.MD, "\n", " ")
+	// 将 HTML 内容渲染为纯文本（表情、图片、@用户等转为可读文本）
+	content := render.ToPlain(m.Content)
+	if content == "" {
+		content = m.MD
+	}
 	return m.UserNickname + ": " + content
 }
 
@@ -134,7 +156,94 @@ type RedPacket struct {
 
 // RedPacketInfo 红包详情
 type RedPacketInfo struct {
-	Code int       `json:"code"`
-	Msg  string    `json:"msg,omitempty"`
-	Data RedPacket `json:"data,omitempty"`
+	Code int                 `json:"code"`
+	Msg  string              `json:"msg,omitempty"`
+	Data RedPacket           `json:"data,omitempty"`
+	Who  []RedPacketReceiver `json:"who,omitempty"` // 已领取者信息（猜拳红包输掉时积分为负）
+}
+
+// AmountOf 返回指定用户在领取名单中的积分变动（猜拳输掉时为负），不在名单中时返回 false
+func (r *RedPacketInfo) AmountOf(username string) (int, bool) {
+	for _, who := range r.Who {
+		if strings.EqualFold(who.UserName, username) {
+			return who.UserMoney, true
+		}
+	}
+	return 0, false
+}
+
+// RedPacketStatus 红包领取状态（WebSocket type 为 redPacketStatus），每有一人领取推送一次
+type RedPacketStatus struct {
+	Type    string `json:"type"`
+	OID     string `json:"oId"`     // 红包消息 ID
+	Count   int    `json:"count"`   // 红包数量
+	Got     int    `json:"got"`     // 已领取数量
+	WhoGive string `json:"whoGive"` // 发送者
+	WhoGot  string `json:"whoGot"`  // 本次领取者
+}
+
+// Exhausted 红包是否已被领完
+func (s *RedPacketStatus) Exhausted() bool {
+	return s.Count > 0 && s.Got >= s.Count
+}
+
+// DefaultBarrageColor 默认弹幕颜色
+const DefaultBarrageColor = "rgba(255,255,255,1)"
+
+// Barrage 弹幕（WebSocket type 为 barrager）
+type Barrage struct {
+	Type            string `json:"type"`
+	OID             string `json:"oId,omitempty"`
+	UserName        string `json:"userName"`
+	UserNickname    string `json:"userNickname"`
+	UserAvatarURL   string `json:"userAvatarURL"`
+	UserAvatarURL20 string `json:"userAvatarURL20,omitempty"`
+	Content         string `json:"barragerContent"` // 弹幕内容（纯文本）
+	Color           string `json:"barragerColor"`   // 弹幕颜色，如 "rgba(255,255,255,1)" 或 "#ffffff"
+}
+
+// BarragePayload 发送弹幕时包裹在 [barrager] 标签中的内容
+type BarragePayload struct {
+	Color   string `json:"color"`
+	Content string `json:"content"`
+}
+
+// BarrageCost 发送弹幕的费用
+type BarrageCost struct {
+	Cost int    // 每次发送花费
+	Unit string // 单位，如 "积分"
+}
+
+// DiscussChanged 话题变更（WebSocket type 为 discussChanged）
+type DiscussChanged struct {
+	Type       string `json:"type"`
+	NewDiscuss string `json:"newDiscuss"` // 新话题
+	WhoChanged string `json:"whoChanged"` // 修改话题的用户
+}
+
+// OnlineInfo 在线用户（WebSocket type 为 online）
+type OnlineInfo struct {
+	Type          string       `json:"type"`
+	OnlineChatCnt int          `json:"onlineChatCnt"` // 在线人数
+	Users         []OnlineUser `json:"users"`         // 在线用户列表
+	Discussing    string       `json:"discussing"`    // 当前话题
+}
+
+// OnlineUser 在线用户
+type OnlineUser struct {
+	UserName      string `json:"userName"`
+	HomePage      string `json:"homePage,omitempty"`
+	UserAvatarURL string `json:"userAvatarURL,omitempty"`
+}
+
+// CustomMessage 用户进入/离开聊天室时的自定义提示（WebSocket type 为 customMessage）
+type CustomMessage struct {
+	Type    string `json:"type"`
+	Message string `json:"message"` // 提示内容，如 "xxx 进入了聊天室"
+}
+
+// RevokeNotice 消息撤回通知（WebSocket type 为 revoke）
+type RevokeNotice struct {
+	Type string `json:"type"`
+	OID  string `json:"oId"` // 被撤回的消息 ID
 }
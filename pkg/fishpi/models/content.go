@@ -0,0 +1,226 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/render"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ParsedMessage 结构化的消息内容
+type ParsedMessage struct {
+	Text       string      // 纯文本内容（不含引用部分）
+	Mentions   []string    // 被 @ 的用户名（去重，保持出现顺序）
+	Quotes     []QuoteRef  // 引用的消息
+	Images     []string    // 图片地址（不含表情）
+	Links      []Link      // 普通链接（不含 @用户 和引用跳转链接）
+	CodeBlocks []CodeBlock // 代码块
+}
+
+// QuoteRef 引用的消息
+type QuoteRef struct {
+	OID      string // 被引用消息的 oId（聊天室消息）
+	UserName string // 被引用消息的发送者
+	Text     string // 被引用的内容（纯文本）
+}
+
+// Link 消息中的链接
+type Link struct {
+	URL  string
+	Text string
+}
+
+// CodeBlock 消息中的代码块
+type CodeBlock struct {
+	Language string
+	Code     string
+}
+
+// Mentioned 判断指定用户是否被 @（忽略大小写）
+func (p *ParsedMessage) Mentioned(userName string) bool {
+	for _, m := range p.Mentions {
+		if strings.EqualFold(m, userName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse 解析聊天消息内容，红包消息只包含祝福语文本
+func (m *ChatMessage) Parse() *ParsedMessage {
+	if m.IsRedPacket() {
+		parsed := &ParsedMessage{}
+		if rp, err := m.GetRedPacket(); err == nil {
+			parsed.Text = rp.Msg
+		}
+		return parsed
+	}
+	if m.Content == "" {
+		return &ParsedMessage{Text: m.MD}
+	}
+	return ParseContent(m.Content)
+}
+
+// Parse 解析清风明月内容
+func (b *Breezemoon) Parse() *ParsedMessage {
+	return ParseContent(b.BreezemoonContent)
+}
+
+// 引用消息的跳转链接，如 https://fishpi.cn/cr#chatroom1730000000000
+var quoteLinkPattern = regexp.MustCompile(`#chatroom(\d+)`)
+
+// ParseContent 解析摸鱼派 HTML 内容
+//
+// 摸鱼派的引用格式为一个包含 "引用 @用户 ↩跳转链接" 的标题，后面紧跟被引用内容的 blockquote。
+func ParseContent(content string) *ParsedMessage {
+	parsed := &ParsedMessage{}
+
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	})
+	if err != nil {
+		parsed.Text = content
+		return parsed
+	}
+
+	root := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+
+	p := &contentParser{parsed: parsed, seenMentions: make(map[string]bool)}
+	p.extractQuotes(root)
+	p.walk(root)
+
+	var b strings.Builder
+	if err := html.Render(&b, root); err == nil {
+		parsed.Text = render.ToPlain(b.String())
+	}
+	return parsed
+}
+
+type contentParser struct {
+	parsed       *ParsedMessage
+	seenMentions map[string]bool
+}
+
+// extractQuotes 提取引用并从树中移除，避免引用内容混入正文
+func (p *contentParser) extractQuotes(root *html.Node) {
+	var headers []*html.Node
+	var find func(n *html.Node)
+	find = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.DataAtom == atom.A && quoteLinkPattern.MatchString(render.Attr(c, "href")) {
+				headers = append(headers, quoteHeader(c))
+				continue
+			}
+			find(c)
+		}
+	}
+	find(root)
+
+	for _, header := range headers {
+		if header.Parent == nil {
+			continue
+		}
+
+		// 被引用内容为紧跟在标题之后的 blockquote，没有则只是普通的跳转链接
+		next := header.NextSibling
+		for next != nil && next.Type != html.ElementNode {
+			next = next.NextSibling
+		}
+		if next == nil || next.DataAtom != atom.Blockquote {
+			continue
+		}
+
+		quote := QuoteRef{}
+		var visit func(n *html.Node)
+		visit = func(n *html.Node) {
+			if n.Type == html.ElementNode && n.DataAtom == atom.A {
+				if m := quoteLinkPattern.FindStringSubmatch(render.Attr(n, "href")); m != nil {
+					quote.OID = m[1]
+				} else if name, ok := render.MentionName(n); ok && quote.UserName == "" {
+					quote.UserName = name
+				}
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				visit(c)
+			}
+		}
+		visit(header)
+
+		var b strings.Builder
+		for c := next.FirstChild; c != nil; c = c.NextSibling {
+			html.Render(&b, c)
+		}
+		quote.Text = render.ToPlain(b.String())
+
+		next.Parent.RemoveChild(next)
+		header.Parent.RemoveChild(header)
+		p.parsed.Quotes = append(p.parsed.Quotes, quote)
+	}
+}
+
+// quoteHeader 返回包含引用跳转链接的块级元素（通常为 h5）
+func quoteHeader(link *html.Node) *html.Node {
+	n := link
+	for n.Parent != nil && n.Parent.Parent != nil {
+		switch n.DataAtom {
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.P:
+			return n
+		}
+		n = n.Parent
+	}
+	return n
+}
+
+func (p *contentParser) walk(n *html.Node) {
+	if n.Type == html.ElementNode {
+		switch n.DataAtom {
+		case atom.A:
+			if name, ok := render.MentionName(n); ok {
+				if key := strings.ToLower(name); !p.seenMentions[key] {
+					p.seenMentions[key] = true
+					p.parsed.Mentions = append(p.parsed.Mentions, name)
+				}
+			} else if href := render.Attr(n, "href"); href != "" && !strings.HasPrefix(href, "#") {
+				p.parsed.Links = append(p.parsed.Links, Link{
+					URL:  href,
+					Text: strings.TrimSpace(render.TextContent(n)),
+				})
+			}
+		case atom.Img:
+			if src := render.Attr(n, "src"); src != "" && !render.IsEmojiImage(n.Attr) {
+				p.parsed.Images = append(p.parsed.Images, src)
+			}
+		case atom.Pre:
+			p.parsed.CodeBlocks = append(p.parsed.CodeBlocks, codeBlock(n))
+			return
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.walk(c)
+	}
+}
+
+// codeBlock 解析 <pre><code class="language-xxx"> 代码块
+func codeBlock(pre *html.Node) CodeBlock {
+	block := CodeBlock{Code: strings.TrimRight(render.TextContent(pre), "\n")}
+	for c := pre.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Code {
+			continue
+		}
+		for _, class := range strings.Fields(render.Attr(c, "class")) {
+			if strings.HasPrefix(class, "language-") {
+				block.Language = strings.TrimPrefix(class, "language-")
+			}
+		}
+	}
+	return block
+}
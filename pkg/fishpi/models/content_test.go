@@ -0,0 +1,47 @@
+package models
+
+import "testing"
+
+func TestParseContentQuotes(t *testing.T) {
+	tests := []struct {
+		name   string
+		html   string
+		quotes []QuoteRef
+		text   string
+		links  int
+	}{
+		{
+			name: "引用",
+			html: `<h5>引用 <a href="/member/alice" class="name-at">alice</a> <a href="https://fishpi.cn/cr#chatroom1730000000000">↩</a></h5>` +
+				`<blockquote><p>原消息</p></blockquote><p>回复</p>`,
+			quotes: []QuoteRef{{OID: "1730000000000", UserName: "alice", Text: "原消息"}},
+			text:   "回复",
+		},
+		{
+			name:  "段落中的跳转链接不是引用",
+			html:  `<p>看这条 <a href="https://fishpi.cn/cr#chatroom1730000000000">消息</a></p><p>后续</p>`,
+			text:  "看这条 消息 (https://fishpi.cn/cr#chatroom1730000000000)\n后续",
+			links: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := ParseContent(tt.html)
+			if len(parsed.Quotes) != len(tt.quotes) {
+				t.Fatalf("quotes = %+v, want %+v", parsed.Quotes, tt.quotes)
+			}
+			for i, q := range tt.quotes {
+				if parsed.Quotes[i] != q {
+					t.Errorf("quote[%d] = %+v, want %+v", i, parsed.Quotes[i], q)
+				}
+			}
+			if parsed.Text != tt.text {
+				t.Errorf("text = %q, want %q", parsed.Text, tt.text)
+			}
+			if len(parsed.Links) != tt.links {
+				t.Errorf("links = %+v, want %d", parsed.Links, tt.links)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+)
+
+// ServerTimeLayout 服务器返回的字符串时间格式，如 "2025-10-29 10:49:55"
+const ServerTimeLayout = "2006-01-02 15:04:05"
+
+// ServerLocation 服务器所在时区（Asia/Shanghai）
+// 系统缺少时区数据库时退化为固定的 UTC+8。
+var ServerLocation = loadServerLocation()
+
+func loadServerLocation() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Shanghai"); err == nil {
+		return loc
+	}
+	return time.FixedZone("CST", 8*60*60)
+}
+
+// ParseServerTime 按服务器时区解析字符串时间
+func ParseServerTime(s string) (time.Time, error) {
+	return time.ParseInLocation(ServerTimeLayout, s, ServerLocation)
+}
+
+// FromMillis 将毫秒时间戳转换为服务器时区的时间，0 返回零值
+func FromMillis(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).In(ServerLocation)
+}
+
+// parseServerTimeOrZero 解析字符串时间，解析失败返回零值
+func parseServerTimeOrZero(s string) time.Time {
+	t, err := ParseServerTime(s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// CreatedAt 消息发送时间，无法解析时返回零值
+func (m *ChatMessage) CreatedAt() time.Time {
+	return parseServerTimeOrZero(m.Time)
+}
+
+// CreatedAt 红包发送时间
+func (rp *RedPacket) CreatedAt() time.Time {
+	return FromMillis(rp.Time)
+}
+
+// ReceivedAt 领取红包的时间，无法解析时返回零值
+func (w *RedPacketReceiver) ReceivedAt() time.Time {
+	return parseServerTimeOrZero(w.Time)
+}
+
+// CreatedAt 清风明月发布时间，优先使用毫秒时间戳
+func (b *Breezemoon) CreatedAt() time.Time {
+	if t := FromMillis(b.BreezemoonCreated); !t.IsZero() {
+		return t
+	}
+	return parseServerTimeOrZero(b.BreezemoonCreateTime)
+}
+
+// UpdatedAt 清风明月更新时间，未更新过时返回发布时间
+func (b *Breezemoon) UpdatedAt() time.Time {
+	if t := FromMillis(b.BreezemoonUpdated); !t.IsZero() {
+		return t
+	}
+	return b.CreatedAt()
+}
+
+// CreatedAt 帖子发布时间，无法解析时返回零值
+func (a *Article) CreatedAt() time.Time {
+	return parseServerTimeOrZero(a.ArticleCreateTimeStr)
+}
+
+// UpdatedAt 帖子更新时间，未更新过时返回发布时间
+func (a *Article) UpdatedAt() time.Time {
+	if t := parseServerTimeOrZero(a.ArticleUpdateTimeStr); !t.IsZero() {
+		return t
+	}
+	return a.CreatedAt()
+}
+
+// CreatedAt 评论时间，无法解析时返回零值
+func (c *Comment) CreatedAt() time.Time {
+	return parseServerTimeOrZero(c.CommentCreateTimeStr)
+}
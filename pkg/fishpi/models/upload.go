@@ -0,0 +1,19 @@
+package models
+
+// UploadResponse 文件上传响应
+type UploadResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg,omitempty"`
+	Data struct {
+		ErrFiles []string          `json:"errFiles"` // 上传失败的文件名
+		SuccMap  map[string]string `json:"succMap"`  // 上传成功的文件名 -> 文件地址
+	} `json:"data"`
+}
+
+// UploadedFile 已上传的文件
+type UploadedFile struct {
+	Name     string // 本地文件名
+	URL      string // 服务器上的文件地址
+	IsImage  bool   // 是否为图片
+	Markdown string // 可直接发送的 Markdown 片段，图片为 ![name](url)，其他文件为 [name](url)
+}
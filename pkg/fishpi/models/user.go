@@ -49,3 +49,54 @@ type LivenessReward struct {
 type LivenessCollectedStatus struct {
 	IsCollectedYesterdayLivenessReward bool `json:"isCollectedYesterdayLivenessReward"`
 }
+
+// FollowUser 关注/粉丝列表中的用户
+type FollowUser struct {
+	OID            string `json:"oId"`
+	UserName       string `json:"userName"`
+	UserNickname   string `json:"userNickname"`
+	UserAvatarURL  string `json:"userAvatarURL"`
+	UserIntro      string `json:"userIntro,omitempty"`
+	UserOnlineFlag bool   `json:"userOnlineFlag"`
+	IsFollowing    bool   `json:"isFollowing"` // 当前登录用户是否已关注该用户
+}
+
+// FollowerList 粉丝列表
+type FollowerList struct {
+	Users      []FollowUser `json:"userHomeFollowerUsers"`
+	Pagination Pagination   `json:"pagination"`
+}
+
+// FollowerListResponse 粉丝列表响应
+type FollowerListResponse struct {
+	Code int          `json:"code"`
+	Msg  string       `json:"msg,omitempty"`
+	Data FollowerList `json:"data"`
+}
+
+// FollowingList 关注列表
+type FollowingList struct {
+	Users      []FollowUser `json:"userHomeFollowingUsers"`
+	Pagination Pagination   `json:"pagination"`
+}
+
+// FollowingListResponse 关注列表响应
+type FollowingListResponse struct {
+	Code int           `json:"code"`
+	Msg  string        `json:"msg,omitempty"`
+	Data FollowingList `json:"data"`
+}
+
+// UsernameSuggestion 用户名前缀查询结果
+type UsernameSuggestion struct {
+	UserName          string `json:"userName"`
+	UserAvatarURL     string `json:"userAvatarURL"`
+	UserNameLowerCase string `json:"userNameLowerCase"`
+}
+
+// UsernameSearchResponse 用户名前缀查询响应
+type UsernameSearchResponse struct {
+	Code int                  `json:"code"`
+	Msg  string               `json:"msg,omitempty"`
+	Data []UsernameSuggestion `json:"data"`
+}
@@ -0,0 +1,122 @@
+package fishpi
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+)
+
+// OnlineDiff 两次 online 推送之间的在线用户变化
+type OnlineDiff struct {
+	Joined  []string  // 新进入聊天室的用户
+	Left    []string  // 离开聊天室的用户
+	Count   int       // 服务端给出的在线人数
+	Time    time.Time // 推送时间
+	Initial bool      // 是否为连接后的第一次推送（此时不计算进出）
+}
+
+// Empty 是否没有用户进出
+func (d *OnlineDiff) Empty() bool {
+	return len(d.Joined) == 0 && len(d.Left) == 0
+}
+
+// OnlineTracker 根据聊天室 online 推送维护当前在线用户，并计算进出变化
+type OnlineTracker struct {
+	mu      sync.Mutex
+	users   map[string]models.OnlineUser // 键为小写用户名
+	count   int
+	updated time.Time
+}
+
+// NewOnlineTracker 创建在线用户跟踪器
+func NewOnlineTracker() *OnlineTracker {
+	return &OnlineTracker{}
+}
+
+// Observe 处理聊天室事件，online 推送返回在线用户变化，其他事件返回 nil
+func (t *OnlineTracker) Observe(e *ChatEvent) *OnlineDiff {
+	info, ok := e.Online()
+	if !ok {
+		return nil
+	}
+	at := e.Received
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return t.Update(info, at)
+}
+
+// Update 用最新的在线列表替换当前在线用户，返回进出变化
+func (t *OnlineTracker) Update(info *models.OnlineInfo, at time.Time) *OnlineDiff {
+	users := make(map[string]models.OnlineUser, len(info.Users))
+	for _, u := range info.Users {
+		if u.UserName == "" {
+			continue
+		}
+		users[strings.ToLower(u.UserName)] = u
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	diff := &OnlineDiff{Count: info.OnlineChatCnt, Time: at, Initial: t.users == nil}
+	if !diff.Initial {
+		for key, u := range users {
+			if _, ok := t.users[key]; !ok {
+				diff.Joined = append(diff.Joined, u.UserName)
+			}
+		}
+		for key, u := range t.users {
+			if _, ok := users[key]; !ok {
+				diff.Left = append(diff.Left, u.UserName)
+			}
+		}
+		sort.Strings(diff.Joined)
+		sort.Strings(diff.Left)
+	}
+
+	t.users = users
+	t.count = info.OnlineChatCnt
+	t.updated = at
+	return diff
+}
+
+// Users 返回当前在线用户，按用户名排序
+func (t *OnlineTracker) Users() []models.OnlineUser {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	users := make([]models.OnlineUser, 0, len(t.users))
+	for _, u := range t.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return strings.ToLower(users[i].UserName) < strings.ToLower(users[j].UserName)
+	})
+	return users
+}
+
+// Count 返回服务端给出的在线人数（可能多于在线列表中的用户数）
+func (t *OnlineTracker) Count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count
+}
+
+// IsOnline 用户是否在线（不区分大小写）
+func (t *OnlineTracker) IsOnline(userName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.users[strings.ToLower(userName)]
+	return ok
+}
+
+// Updated 返回最近一次 online 推送的时间，尚未收到推送时为零值
+func (t *OnlineTracker) Updated() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.updated
+}
@@ -0,0 +1,118 @@
+package fishpi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+)
+
+func onlineEvent(t *testing.T, count int, names ...string) *ChatEvent {
+	t.Helper()
+	info := models.OnlineInfo{Type: models.ChatTypeOnline, OnlineChatCnt: count}
+	for _, name := range names {
+		info.Users = append(info.Users, models.OnlineUser{UserName: name})
+	}
+	raw, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ChatEvent{Type: models.ChatTypeOnline, Raw: raw, Received: time.Unix(1730000000, 0)}
+}
+
+func TestOnlineTrackerObserve(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   func(t *testing.T) *ChatEvent
+		nilDiff bool
+		initial bool
+		joined  []string
+		left    []string
+		count   int
+		online  []string
+	}{
+		{
+			name:    "首次推送不计算进出",
+			event:   func(t *testing.T) *ChatEvent { return onlineEvent(t, 3, "alice", "Bob", "") },
+			initial: true,
+			count:   3,
+			online:  []string{"alice", "Bob"},
+		},
+		{
+			name:    "非 online 事件",
+			event:   func(t *testing.T) *ChatEvent { return &ChatEvent{Type: models.ChatTypeMessage} },
+			nilDiff: true,
+			count:   3,
+			online:  []string{"alice", "Bob"},
+		},
+		{
+			name:   "用户进出",
+			event:  func(t *testing.T) *ChatEvent { return onlineEvent(t, 3, "bob", "dave", "carol") },
+			joined: []string{"carol", "dave"},
+			left:   []string{"alice"},
+			count:  3,
+			online: []string{"bob", "carol", "dave"},
+		},
+		{
+			name:   "在线列表不变",
+			event:  func(t *testing.T) *ChatEvent { return onlineEvent(t, 5, "carol", "dave", "BOB") },
+			count:  5,
+			online: []string{"BOB", "carol", "dave"},
+		},
+		{
+			name:   "全部离开",
+			event:  func(t *testing.T) *ChatEvent { return onlineEvent(t, 0) },
+			left:   []string{"BOB", "carol", "dave"},
+			online: []string{},
+		},
+	}
+
+	// 各用例依次作用于同一个跟踪器
+	tracker := NewOnlineTracker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := tracker.Observe(tt.event(t))
+			if tt.nilDiff {
+				if diff != nil {
+					t.Fatalf("diff = %+v, want nil", diff)
+				}
+			} else {
+				if diff == nil {
+					t.Fatal("diff = nil")
+				}
+				if diff.Initial != tt.initial {
+					t.Errorf("Initial = %v, want %v", diff.Initial, tt.initial)
+				}
+				if !reflect.DeepEqual(diff.Joined, tt.joined) {
+					t.Errorf("Joined = %q, want %q", diff.Joined, tt.joined)
+				}
+				if !reflect.DeepEqual(diff.Left, tt.left) {
+					t.Errorf("Left = %q, want %q", diff.Left, tt.left)
+				}
+				if diff.Empty() != (len(tt.joined) == 0 && len(tt.left) == 0) {
+					t.Errorf("Empty = %v", diff.Empty())
+				}
+			}
+
+			if tracker.Count() != tt.count {
+				t.Errorf("Count = %d, want %d", tracker.Count(), tt.count)
+			}
+			names := []string{}
+			for _, u := range tracker.Users() {
+				names = append(names, u.UserName)
+			}
+			if !reflect.DeepEqual(names, tt.online) {
+				t.Errorf("Users = %q, want %q", names, tt.online)
+			}
+		})
+	}
+
+	if tracker.IsOnline("bob") {
+		t.Error("IsOnline(bob) = true after everyone left")
+	}
+	if tracker.Updated().IsZero() {
+		t.Error("Updated is zero after online events")
+	}
+}
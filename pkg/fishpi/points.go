@@ -0,0 +1,89 @@
+package fishpi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
+	"go.uber.org/zap"
+)
+
+// TransferPoints 转账积分给指定用户
+// toUser: 收款用户名
+// amount: 转账积分（正整数）
+// memo: 转账备注
+// 转账前会查询当前积分余额，余额不足时直接返回错误；成功后记录到账本（如已配置）。
+func (c *Client) TransferPoints(toUser string, amount int, memo string) error {
+	if c.APIKey == "" {
+		return fmt.Errorf("API Key未设置，请先登录")
+	}
+	toUser = strings.TrimSpace(toUser)
+	if toUser == "" {
+		return fmt.Errorf("收款用户名不能为空")
+	}
+	if amount <= 0 {
+		return fmt.Errorf("转账积分必须大于0")
+	}
+
+	// 校验余额
+	user, err := c.GetUser()
+	if err != nil {
+		return fmt.Errorf("查询积分余额失败: %w", err)
+	}
+	if strings.EqualFold(user.UserName, toUser) {
+		return fmt.Errorf("不能给自己转账")
+	}
+	if user.UserPoint < amount {
+		return fmt.Errorf("积分余额不足: 当前 %d，需要 %d", user.UserPoint, amount)
+	}
+
+	c.Logger.Info("转账积分",
+		zap.String("to_user", toUser),
+		zap.Int("amount", amount),
+		zap.String("memo", memo),
+	)
+
+	reqBody := map[string]interface{}{
+		"apiKey":   c.APIKey,
+		"userName": toUser,
+		"amount":   amount,
+		"memo":     memo,
+	}
+
+	// apiKey 已经在请求体中
+	resp, err := c.doRequest(http.MethodPost, "/point/transfer", reqBody, false)
+	if err != nil {
+		return err
+	}
+
+	var result models.ActionResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return err
+	}
+
+	if result.Code != 0 {
+		return fmt.Errorf("转账失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("转账积分成功", zap.String("to_user", toUser), zap.Int("amount", amount))
+
+	c.recordLedger(LedgerEntry{
+		Kind:         LedgerTransferOut,
+		Counterparty: toUser,
+		Amount:       -amount,
+		Memo:         memo,
+	})
+	return nil
+}
+
+// recordLedger 写入账本，未配置账本时忽略；写入失败只记录日志，不影响主流程
+func (c *Client) recordLedger(entry LedgerEntry) {
+	if c.Ledger == nil {
+		return
+	}
+	if err := c.Ledger.Append(entry); err != nil {
+		c.Logger.Warn("写入积分账本失败", zap.Error(err))
+	}
+}
@@ -0,0 +1,518 @@
+package fishpi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
+	"go.uber.org/zap"
+)
+
+// DefaultRedPacketHistory 红包跟踪器默认保留的红包数量
+const DefaultRedPacketHistory = 1000
+
+// 猜拳结果
+const (
+	GestureResultNone = ""     // 非猜拳红包或未领取
+	GestureResultWin  = "win"  // 赢，获得积分
+	GestureResultDraw = "draw" // 平局，积分不变
+	GestureResultLose = "lose" // 输，扣除积分
+)
+
+// RedPacketGrab 一次领取记录
+type RedPacketGrab struct {
+	UserName string    `json:"user_name"`
+	Amount   int       `json:"amount"` // 领取到的积分，状态帧中没有金额时为 0
+	Known    bool      `json:"known"`  // 金额是否已知（来自领取结果）
+	Time     time.Time `json:"time,omitempty"`
+}
+
+// TrackedRedPacket 被跟踪的红包，从发出到领完的完整过程
+type TrackedRedPacket struct {
+	OID         string          `json:"oid"`
+	Sender      string          `json:"sender"`
+	Type        string          `json:"type"` // random, average, specify, heartbeat, rockPaperScissors
+	Msg         string          `json:"msg"`
+	Money       int             `json:"money"`
+	Count       int             `json:"count"`
+	Got         int             `json:"got"`
+	SentAt      time.Time       `json:"sent_at"`
+	ExhaustedAt time.Time       `json:"exhausted_at,omitempty"` // 领完时间，未领完为零值
+	Grabs       []RedPacketGrab `json:"grabs,omitempty"`
+
+	Opened        bool   `json:"opened"`                   // 我是否领取过
+	MyAmount      int    `json:"my_amount"`                // 我领到的积分（猜拳输时为负）
+	Gesture       int    `json:"gesture"`                  // 我出的拳，-1 表示非猜拳
+	GestureResult string `json:"gesture_result,omitempty"` // 猜拳结果，见 GestureResult* 常量
+}
+
+// Exhausted 红包是否已领完
+func (p *TrackedRedPacket) Exhausted() bool {
+	return !p.ExhaustedAt.IsZero()
+}
+
+// grab 记录或更新领取者，已知金额的记录不会被状态帧覆盖
+func (p *TrackedRedPacket) grab(g RedPacketGrab) {
+	for i := range p.Grabs {
+		if strings.EqualFold(p.Grabs[i].UserName, g.UserName) {
+			if g.Known {
+				p.Grabs[i] = g
+			}
+			return
+		}
+	}
+	p.Grabs = append(p.Grabs, g)
+}
+
+// RedPacketSummary 红包统计
+type RedPacketSummary struct {
+	Seen       int            // 观察到的红包数
+	Exhausted  int            // 已领完的红包数
+	Opened     int            // 我领取的红包数
+	Won        int            // 我领取到积分的次数
+	MyTotal    int            // 我领取到的积分合计（含猜拳输掉的负值）
+	BestAmount int            // 单次领取最多的积分
+	BestOID    string         // 单次领取最多的红包
+	Gestures   map[string]int // 猜拳结果计数
+	BySender   map[string]int // 按发送者统计的红包数
+	AvgDrain   time.Duration  // 红包从发出到领完的平均耗时
+}
+
+// RedPacketTracker 红包跟踪器
+// 通过聊天室事件（红包消息和 redPacketStatus 状态帧）和领取结果跟踪每个红包的领取情况。
+type RedPacketTracker struct {
+	me      string
+	limit   int
+	mu      sync.Mutex
+	saveMu  sync.Mutex // 串行化 Save，保证后取的快照后写入
+	packets map[string]*TrackedRedPacket
+	order   []string // 按发出顺序排列的红包 ID
+}
+
+// NewRedPacketTracker 创建红包跟踪器，me 为当前用户名
+func NewRedPacketTracker(me string) *RedPacketTracker {
+	return &RedPacketTracker{
+		me:      me,
+		limit:   DefaultRedPacketHistory,
+		packets: make(map[string]*TrackedRedPacket),
+	}
+}
+
+// LoadRedPacketTracker 从文件加载跟踪记录，文件不存在时返回空的跟踪器
+func LoadRedPacketTracker(path, me string) (*RedPacketTracker, error) {
+	t := NewRedPacketTracker(me)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取红包记录失败: %w", err)
+	}
+
+	var packets []TrackedRedPacket
+	if err := json.Unmarshal(data, &packets); err != nil {
+		return nil, fmt.Errorf("解析红包记录失败: %w", err)
+	}
+	for i := range packets {
+		p := packets[i]
+		t.packets[p.OID] = &p
+		t.order = append(t.order, p.OID)
+	}
+	return t, nil
+}
+
+// Save 将跟踪记录保存到文件，可并发调用
+// 先写入同目录下的临时文件再重命名，避免中途失败留下不完整的文件。
+func (t *RedPacketTracker) Save(path string) error {
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+
+	data, err := json.MarshalIndent(t.Packets(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化红包记录失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("保存红包记录失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("保存红包记录失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("保存红包记录失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("保存红包记录失败: %w", err)
+	}
+	return nil
+}
+
+// Observe 处理聊天室事件，返回事件涉及的红包（非红包事件返回 nil）
+func (t *RedPacketTracker) Observe(e *ChatEvent) *TrackedRedPacket {
+	received := e.Received
+	if received.IsZero() {
+		received = time.Now()
+	}
+
+	switch {
+	case e.Type == models.ChatTypeRedPacketStatus:
+		var status models.RedPacketStatus
+		if err := json.Unmarshal(e.Raw, &status); err != nil {
+			return nil
+		}
+		return t.observeStatus(&status, received)
+	case e.Message.IsRedPacket():
+		rp, err := e.Message.GetRedPacket()
+		if err != nil {
+			return nil
+		}
+		return t.observeSend(e.Message, rp, received)
+	}
+	return nil
+}
+
+func (t *RedPacketTracker) observeSend(msg *models.ChatMessage, rp *models.RedPacketContent, now time.Time) *TrackedRedPacket {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.get(msg.OID)
+	p.Sender = msg.UserName
+	p.Type = rp.Type
+	p.Msg = rp.Msg
+	p.Money = rp.Money
+	p.Count = rp.Count
+	if rp.Got > p.Got {
+		p.Got = rp.Got
+	}
+	if p.SentAt.IsZero() {
+		p.SentAt = msg.CreatedAt()
+	}
+	for _, who := range rp.Who {
+		p.grab(RedPacketGrab{UserName: who.UserName, Amount: who.UserMoney, Known: true, Time: who.ReceivedAt()})
+	}
+	t.checkExhausted(p, now)
+	return t.copy(p)
+}
+
+func (t *RedPacketTracker) observeStatus(status *models.RedPacketStatus, now time.Time) *TrackedRedPacket {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p := t.get(status.OID)
+	if p.Sender == "" {
+		p.Sender = status.WhoGive
+	}
+	if status.Count > 0 {
+		p.Count = status.Count
+	}
+	if status.Got > p.Got {
+		p.Got = status.Got
+	}
+	if status.WhoGot != "" {
+		p.grab(RedPacketGrab{UserName: status.WhoGot, Time: now})
+	}
+	t.checkExhausted(p, now)
+	return t.copy(p)
+}
+
+// RecordOpen 记录我的领取结果，gesture 为出拳（-1 表示非猜拳红包）
+func (t *RedPacketTracker) RecordOpen(oId string, gesture int, result *models.RedPacketInfo) *TrackedRedPacket {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	p := t.get(oId)
+	if p.Sender == "" {
+		p.Sender = result.Data.UserName
+	}
+	if p.Msg == "" {
+		p.Msg = result.Data.Msg
+	}
+	if p.Type == "" {
+		p.Type = result.Data.Type
+	}
+	if result.Data.Count > 0 {
+		p.Count = result.Data.Count
+	}
+	if result.Data.Got > p.Got {
+		p.Got = result.Data.Got
+	}
+
+	// 领取名单中包含我的金额，Data.Money 是红包总额不能作为我的金额
+	var amount int
+	for _, who := range result.Who {
+		p.grab(RedPacketGrab{UserName: who.UserName, Amount: who.UserMoney, Known: true, Time: who.ReceivedAt()})
+		if strings.EqualFold(who.UserName, t.me) {
+			amount = who.UserMoney
+		}
+	}
+	if t.me != "" {
+		p.grab(RedPacketGrab{UserName: t.me, Amount: amount, Known: true, Time: now})
+	}
+
+	p.Opened = true
+	p.MyAmount = amount
+	p.Gesture = gesture
+	p.GestureResult = GestureResultNone
+	if gesture >= 0 {
+		switch {
+		case amount > 0:
+			p.GestureResult = GestureResultWin
+		case amount < 0:
+			p.GestureResult = GestureResultLose
+		default:
+			p.GestureResult = GestureResultDraw
+		}
+	}
+	t.checkExhausted(p, now)
+	return t.copy(p)
+}
+
+// Packet 返回指定红包的跟踪记录
+func (t *RedPacketTracker) Packet(oId string) (*TrackedRedPacket, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.packets[oId]
+	if !ok {
+		return nil, false
+	}
+	return t.copy(p), true
+}
+
+// Packets 按发出顺序返回全部跟踪记录
+func (t *RedPacketTracker) Packets() []TrackedRedPacket {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	packets := make([]TrackedRedPacket, 0, len(t.order))
+	for _, id := range t.order {
+		packets = append(packets, *t.copy(t.packets[id]))
+	}
+	return packets
+}
+
+// Summarize 汇总 since 之后（含）发出的红包，since 为零值时汇总全部
+func (t *RedPacketTracker) Summarize(since time.Time) *RedPacketSummary {
+	summary := &RedPacketSummary{
+		Gestures: make(map[string]int),
+		BySender: make(map[string]int),
+	}
+
+	var drain time.Duration
+	for _, p := range t.Packets() {
+		if !since.IsZero() && p.SentAt.Before(since) {
+			continue
+		}
+		summary.Seen++
+		if p.Sender != "" {
+			summary.BySender[p.Sender]++
+		}
+		if p.Exhausted() {
+			summary.Exhausted++
+			if !p.SentAt.IsZero() && p.ExhaustedAt.After(p.SentAt) {
+				drain += p.ExhaustedAt.Sub(p.SentAt)
+			}
+		}
+		if !p.Opened {
+			continue
+		}
+		summary.Opened++
+		summary.MyTotal += p.MyAmount
+		if p.MyAmount > 0 {
+			summary.Won++
+		}
+		if p.MyAmount > summary.BestAmount {
+			summary.BestAmount = p.MyAmount
+			summary.BestOID = p.OID
+		}
+		if p.GestureResult != GestureResultNone {
+			summary.Gestures[p.GestureResult]++
+		}
+	}
+	if summary.Exhausted > 0 {
+		summary.AvgDrain = drain / time.Duration(summary.Exhausted)
+	}
+	return summary
+}
+
+// TopSenders 按发出红包数量排序的发送者，最多返回 limit 个
+func (s *RedPacketSummary) TopSenders(limit int) []string {
+	senders := make([]string, 0, len(s.BySender))
+	for name := range s.BySender {
+		senders = append(senders, name)
+	}
+	sort.Slice(senders, func(i, j int) bool {
+		if s.BySender[senders[i]] != s.BySender[senders[j]] {
+			return s.BySender[senders[i]] > s.BySender[senders[j]]
+		}
+		return senders[i] < senders[j]
+	})
+	if limit > 0 && len(senders) > limit {
+		senders = senders[:limit]
+	}
+	return senders
+}
+
+// get 获取或创建红包记录，超出保留数量时淘汰最早的红包，调用方需持有锁
+func (t *RedPacketTracker) get(oId string) *TrackedRedPacket {
+	if p, ok := t.packets[oId]; ok {
+		return p
+	}
+	p := &TrackedRedPacket{OID: oId, Gesture: -1}
+	t.packets[oId] = p
+	t.order = append(t.order, oId)
+	if len(t.order) > t.limit {
+		delete(t.packets, t.order[0])
+		t.order = t.order[1:]
+	}
+	return p
+}
+
+// Live 将跟踪器中已观察到的领取情况合并到红包内容中，返回新的红包内容
+// 发送帧中的 Got 和 Who 只反映发出时的状态，评估前用 redPacketStatus 帧和领取结果更新。
+// 状态帧只有领取者没有金额，这些领取者只计入已领取数量，不参与亏损比例计算。
+func (t *RedPacketTracker) Live(oId string, rp *models.RedPacketContent) *models.RedPacketContent {
+	live := *rp
+	live.Who = append([]models.RedPacketReceiver(nil), rp.Who...)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.packets[oId]
+	if !ok {
+		return &live
+	}
+
+	if p.Got > live.Got {
+		live.Got = p.Got
+	}
+	for _, g := range p.Grabs {
+		if !g.Known {
+			continue
+		}
+		found := false
+		for _, who := range live.Who {
+			if strings.EqualFold(who.UserName, g.UserName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			live.Who = append(live.Who, models.RedPacketReceiver{UserName: g.UserName, UserMoney: g.Amount})
+		}
+	}
+	return &live
+}
+
+func (t *RedPacketTracker) checkExhausted(p *TrackedRedPacket, now time.Time) {
+	if p.ExhaustedAt.IsZero() && p.Count > 0 && p.Got >= p.Count {
+		p.ExhaustedAt = now
+	}
+}
+
+// copy 返回记录的副本，避免调用方与跟踪器共享切片
+func (t *RedPacketTracker) copy(p *TrackedRedPacket) *TrackedRedPacket {
+	c := *p
+	c.Grabs = append([]RedPacketGrab(nil), p.Grabs...)
+	return &c
+}
+
+// 心跳红包风险控制默认值
+const (
+	DefaultHeartbeatMinExpected  = 1.0 // 期望收益低于 1 积分时不领取
+	DefaultHeartbeatMaxLossRatio = 0.5 // 已领取者中超过一半亏损时不领取
+)
+
+// RedPacketPolicy 红包领取策略，目前只约束心跳红包
+type RedPacketPolicy struct {
+	HeartbeatMinExpected  float64 // 心跳红包期望收益（剩余积分 / 剩余个数）的下限
+	HeartbeatMaxLossRatio float64 // 已领取者中亏损（积分为负）比例的上限
+}
+
+// DefaultRedPacketPolicy 默认红包领取策略
+func DefaultRedPacketPolicy() RedPacketPolicy {
+	return RedPacketPolicy{
+		HeartbeatMinExpected:  DefaultHeartbeatMinExpected,
+		HeartbeatMaxLossRatio: DefaultHeartbeatMaxLossRatio,
+	}
+}
+
+// RedPacketDecision 是否领取红包的评估结果
+type RedPacketDecision struct {
+	Open          bool    // 是否领取
+	Remaining     int     // 剩余积分（总额减去已知的领取金额）
+	RemainCount   int     // 剩余个数
+	ExpectedValue float64 // 期望收益
+	LossRatio     float64 // 已领取者中亏损的比例
+	Reason        string  // 决策原因
+}
+
+// Evaluate 根据红包已领取情况评估是否领取
+// 心跳红包领取可能扣除积分，根据 Money、Count、Got 和 Who 估算剩余期望收益和亏损比例，
+// 任一指标超出阈值即拒绝领取；其他类型的红包只检查是否已领完。
+func (p RedPacketPolicy) Evaluate(rp *models.RedPacketContent) RedPacketDecision {
+	d := RedPacketDecision{RemainCount: rp.Count - rp.Got}
+	if d.RemainCount <= 0 {
+		d.Reason = "红包已领完"
+		return d
+	}
+
+	d.Remaining = rp.Money
+	losers := 0
+	for _, who := range rp.Who {
+		d.Remaining -= who.UserMoney
+		if who.UserMoney < 0 {
+			losers++
+		}
+	}
+	d.ExpectedValue = float64(d.Remaining) / float64(d.RemainCount)
+	if len(rp.Who) > 0 {
+		d.LossRatio = float64(losers) / float64(len(rp.Who))
+	}
+
+	if rp.Type != "heartbeat" {
+		d.Open = true
+		d.Reason = "非心跳红包"
+		return d
+	}
+
+	switch {
+	case d.ExpectedValue < p.HeartbeatMinExpected:
+		d.Reason = fmt.Sprintf("期望收益 %.1f 低于阈值 %.1f", d.ExpectedValue, p.HeartbeatMinExpected)
+	case d.LossRatio > p.HeartbeatMaxLossRatio:
+		d.Reason = fmt.Sprintf("已领取的 %d 人中 %d 人亏损，比例 %.0f%% 超过阈值 %.0f%%",
+			len(rp.Who), losers, d.LossRatio*100, p.HeartbeatMaxLossRatio*100)
+	default:
+		d.Open = true
+		d.Reason = fmt.Sprintf("期望收益 %.1f，亏损比例 %.0f%%，在阈值范围内", d.ExpectedValue, d.LossRatio*100)
+	}
+	return d
+}
+
+// ShouldOpenRedPacket 评估是否领取红包，并在日志中记录决策原因
+func (c *Client) ShouldOpenRedPacket(oId string, rp *models.RedPacketContent, policy RedPacketPolicy) RedPacketDecision {
+	d := policy.Evaluate(rp)
+	c.Logger.Info("红包领取评估",
+		zap.String("oId", oId),
+		zap.String("type", rp.Type),
+		zap.Int("money", rp.Money),
+		zap.Int("count", rp.Count),
+		zap.Int("got", rp.Got),
+		zap.Int("remaining", d.Remaining),
+		zap.Float64("expected", d.ExpectedValue),
+		zap.Float64("loss_ratio", d.LossRatio),
+		zap.Bool("open", d.Open),
+		zap.String("reason", d.Reason),
+	)
+	return d
+}
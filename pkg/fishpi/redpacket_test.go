@@ -0,0 +1,153 @@
+package fishpi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+)
+
+func TestRedPacketTrackerConcurrentSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redpackets.json")
+	tracker := NewRedPacketTracker("me")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			oId := fmt.Sprint(1730000000000 + i)
+			tracker.RecordOpen(oId, -1, &models.RedPacketInfo{
+				Data: models.RedPacket{OID: oId, UserName: "alice", Money: 100, Count: 5, Got: 1},
+				Who:  []models.RedPacketReceiver{{UserName: "me", UserMoney: 7}},
+			})
+			if err := tracker.Save(path); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	loaded, err := LoadRedPacketTracker(path, "me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	packets := loaded.Packets()
+	if len(packets) != 20 {
+		t.Fatalf("loaded %d packets, want 20", len(packets))
+	}
+	for _, p := range packets {
+		if p.MyAmount != 7 {
+			t.Errorf("packet %s MyAmount = %d, want 7", p.OID, p.MyAmount)
+		}
+	}
+}
+
+func TestRedPacketPolicyEvaluate(t *testing.T) {
+	who := func(amounts ...int) []models.RedPacketReceiver {
+		list := make([]models.RedPacketReceiver, len(amounts))
+		for i, a := range amounts {
+			list[i] = models.RedPacketReceiver{UserName: fmt.Sprint("user", i), UserMoney: a}
+		}
+		return list
+	}
+
+	tests := []struct {
+		name      string
+		rp        models.RedPacketContent
+		open      bool
+		remaining int
+		lossRatio float64
+		reason    string
+	}{
+		{
+			name:   "已领完",
+			rp:     models.RedPacketContent{Type: "random", Money: 100, Count: 2, Got: 2, Who: who(60, 40)},
+			reason: "红包已领完",
+		},
+		{
+			name:      "非心跳红包",
+			rp:        models.RedPacketContent{Type: "random", Money: 100, Count: 5, Got: 1, Who: who(30)},
+			open:      true,
+			remaining: 70,
+		},
+		{
+			name:      "刚发出的心跳红包",
+			rp:        models.RedPacketContent{Type: "heartbeat", Money: 100, Count: 5},
+			open:      true,
+			remaining: 100,
+		},
+		{
+			name:      "心跳红包亏损者过多",
+			rp:        models.RedPacketContent{Type: "heartbeat", Money: 100, Count: 5, Got: 3, Who: who(-10, -20, 30)},
+			remaining: 100,
+			lossRatio: 2.0 / 3,
+			reason:    "3 人中 2 人亏损",
+		},
+		{
+			name:      "心跳红包亏损比例在阈值内",
+			rp:        models.RedPacketContent{Type: "heartbeat", Money: 100, Count: 5, Got: 2, Who: who(-10, 30)},
+			open:      true,
+			remaining: 80,
+			lossRatio: 0.5,
+		},
+		{
+			name:      "心跳红包期望收益过低",
+			rp:        models.RedPacketContent{Type: "heartbeat", Money: 10, Count: 5, Got: 2, Who: who(8, 5)},
+			remaining: -3,
+			reason:    "期望收益",
+		},
+	}
+
+	policy := DefaultRedPacketPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := policy.Evaluate(&tt.rp)
+			if d.Open != tt.open {
+				t.Errorf("Open = %v, want %v (%s)", d.Open, tt.open, d.Reason)
+			}
+			if d.Remaining != tt.remaining {
+				t.Errorf("Remaining = %d, want %d", d.Remaining, tt.remaining)
+			}
+			if d.LossRatio != tt.lossRatio {
+				t.Errorf("LossRatio = %v, want %v", d.LossRatio, tt.lossRatio)
+			}
+			if tt.reason != "" && !strings.Contains(d.Reason, tt.reason) {
+				t.Errorf("Reason = %q, want containing %q", d.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestRedPacketTrackerLive(t *testing.T) {
+	tracker := NewRedPacketTracker("me")
+	sent := &models.RedPacketContent{Type: "heartbeat", Money: 100, Count: 5}
+
+	// 状态帧只更新已领取数量，领取结果带有金额
+	tracker.observeStatus(&models.RedPacketStatus{OID: "1", Count: 5, Got: 1, WhoGot: "alice"}, time.Now())
+	tracker.observeStatus(&models.RedPacketStatus{OID: "1", Count: 5, Got: 3, WhoGot: "bob"}, time.Now())
+	tracker.RecordOpen("1", -1, &models.RedPacketInfo{
+		Data: models.RedPacket{OID: "1", Count: 5, Got: 3},
+		Who:  []models.RedPacketReceiver{{UserName: "carol", UserMoney: -20}, {UserName: "me", UserMoney: -5}},
+	})
+
+	live := tracker.Live("1", sent)
+	if live.Got != 3 {
+		t.Errorf("Got = %d, want 3", live.Got)
+	}
+	if len(live.Who) != 2 {
+		t.Fatalf("Who = %+v, want the two receivers with known amounts", live.Who)
+	}
+	if len(sent.Who) != 0 || sent.Got != 0 {
+		t.Errorf("Live modified the original content: %+v", sent)
+	}
+
+	d := DefaultRedPacketPolicy().Evaluate(live)
+	if d.Open || d.LossRatio != 1 {
+		t.Errorf("decision = %+v, want rejected with loss ratio 1", d)
+	}
+}
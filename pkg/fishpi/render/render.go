@@ -0,0 +1,425 @@
+// Package render 将摸鱼派消息中的 HTML 内容转换为终端文本。
+//
+// 支持链接、图片、表情图片、@用户、代码块、引用、列表等常见元素，
+// 可输出带 ANSI 样式的文本或纯文本。
+package render
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ANSI 样式
+const (
+	ansiReset     = "\033[0m"
+	ansiBold      = "\033[1m"
+	ansiDim       = "\033[2m"
+	ansiItalic    = "\033[3m"
+	ansiUnderline = "\033[4m"
+	ansiStrike    = "\033[9m"
+	ansiGreen     = "\033[32m"
+	ansiYellow    = "\033[33m"
+	ansiBlue      = "\033[34m"
+	ansiMagenta   = "\033[35m"
+	ansiCyan      = "\033[36m"
+)
+
+// ToANSI 将 HTML 内容转换为带 ANSI 样式的终端文本
+func ToANSI(content string) string {
+	return Render(content, true)
+}
+
+// ToPlain 将 HTML 内容转换为纯文本
+func ToPlain(content string) string {
+	return Render(content, false)
+}
+
+// Render 将 HTML 内容转换为终端文本，color 为 false 时不输出 ANSI 样式
+func Render(content string, color bool) string {
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "div",
+		DataAtom: atom.Div,
+	})
+	if err != nil {
+		return content
+	}
+
+	r := &renderer{color: color, lineStart: true}
+	for _, n := range nodes {
+		r.node(n)
+	}
+	return strings.TrimSpace(r.b.String())
+}
+
+type renderer struct {
+	b         strings.Builder
+	color     bool
+	styles    []string // 当前生效的样式栈
+	quote     int      // 引用嵌套层数
+	pre       int      // 是否处于 <pre> 中
+	lists     []int    // 列表栈：-1 表示无序列表，否则为有序列表的当前序号
+	lineStart bool     // 当前是否位于行首
+	spaces    int      // 尚未写出的行尾空格数，换行时直接丢弃
+}
+
+func (r *renderer) node(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		r.text(n.Data)
+		return
+	case html.ElementNode:
+	default:
+		r.children(n)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		r.newline()
+	case atom.P, atom.Div, atom.Section, atom.Details, atom.Summary, atom.Table, atom.Tr:
+		r.block()
+		r.children(n)
+		r.block()
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		r.block()
+		r.styled(n, ansiBold)
+		r.block()
+	case atom.Hr:
+		r.block()
+		r.styledText(strings.Repeat("─", 20), ansiDim)
+		r.block()
+	case atom.Blockquote:
+		r.block()
+		r.quote++
+		r.children(n)
+		r.quote--
+		r.block()
+	case atom.Pre:
+		r.block()
+		r.pre++
+		r.push(ansiGreen)
+		r.children(n)
+		r.pop()
+		r.pre--
+		r.block()
+	case atom.Code:
+		if r.pre > 0 {
+			r.children(n)
+		} else if r.color {
+			r.styled(n, ansiYellow)
+		} else {
+			r.write("`")
+			r.children(n)
+			r.write("`")
+		}
+	case atom.Ul, atom.Ol:
+		r.block()
+		if n.DataAtom == atom.Ol {
+			r.lists = append(r.lists, 1)
+		} else {
+			r.lists = append(r.lists, -1)
+		}
+		r.children(n)
+		r.lists = r.lists[:len(r.lists)-1]
+		r.block()
+	case atom.Li:
+		r.listItem(n)
+	case atom.Td, atom.Th:
+		r.children(n)
+		r.write(" ")
+	case atom.Strong, atom.B:
+		r.styled(n, ansiBold)
+	case atom.Em, atom.I:
+		r.styled(n, ansiItalic)
+	case atom.Del, atom.S, atom.Strike:
+		r.styled(n, ansiStrike)
+	case atom.A:
+		r.link(n)
+	case atom.Img:
+		r.image(n)
+	case atom.Audio, atom.Video, atom.Iframe, atom.Source:
+		r.media(n)
+	case atom.Script, atom.Style, atom.Head:
+		// 忽略
+	default:
+		r.children(n)
+	}
+}
+
+func (r *renderer) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		r.node(c)
+	}
+}
+
+// text 输出文本节点，<pre> 之外会折叠空白
+func (r *renderer) text(s string) {
+	if r.pre > 0 {
+		r.write(s)
+		return
+	}
+
+	collapsed := strings.Join(strings.Fields(s), " ")
+	if collapsed == "" {
+		if s != "" && !r.lineStart {
+			r.write(" ")
+		}
+		return
+	}
+	if hasLeadingSpace(s) && !r.lineStart {
+		collapsed = " " + collapsed
+	}
+	if hasTrailingSpace(s) {
+		collapsed += " "
+	}
+	r.write(collapsed)
+}
+
+// write 写入文本，在每行开头补上引用前缀
+func (r *renderer) write(s string) {
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			r.newline()
+		}
+		if line == "" {
+			continue
+		}
+		if r.lineStart {
+			if r.pre == 0 {
+				line = strings.TrimLeft(line, " ")
+				if line == "" {
+					continue
+				}
+			}
+			r.linePrefix()
+		}
+		r.emitText(line)
+		r.lineStart = false
+	}
+}
+
+// emit 写出暂存的空格后写入 s
+func (r *renderer) emit(s string) {
+	if r.spaces > 0 {
+		r.b.WriteString(strings.Repeat(" ", r.spaces))
+		r.spaces = 0
+	}
+	r.b.WriteString(s)
+}
+
+// emitText 写入文本，行尾空格先暂存，后面还有内容时才写出
+func (r *renderer) emitText(s string) {
+	trimmed := strings.TrimRight(s, " ")
+	r.emit(trimmed)
+	r.spaces += len(s) - len(trimmed)
+}
+
+func (r *renderer) linePrefix() {
+	r.lineStart = false
+	if r.quote == 0 {
+		return
+	}
+	prefix := strings.Repeat("│ ", r.quote)
+	if r.color {
+		r.emit(ansiReset + ansiDim + prefix + ansiReset + strings.Join(r.styles, ""))
+	} else {
+		r.emitText(prefix)
+	}
+}
+
+// newline 换行（行尾的空格会被去掉）
+func (r *renderer) newline() {
+	r.spaces = 0
+	r.b.WriteString("\n")
+	r.lineStart = true
+}
+
+// block 块级元素边界：不在行首时换行
+func (r *renderer) block() {
+	if !r.lineStart {
+		r.newline()
+	}
+}
+
+func (r *renderer) push(style string) {
+	r.styles = append(r.styles, style)
+	if r.color {
+		r.emit(style)
+	}
+}
+
+func (r *renderer) pop() {
+	r.styles = r.styles[:len(r.styles)-1]
+	if r.color {
+		r.emit(ansiReset + strings.Join(r.styles, ""))
+	}
+}
+
+func (r *renderer) styled(n *html.Node, style string) {
+	r.push(style)
+	r.children(n)
+	r.pop()
+}
+
+func (r *renderer) styledText(s, style string) {
+	r.push(style)
+	r.write(s)
+	r.pop()
+}
+
+func (r *renderer) listItem(n *html.Node) {
+	r.block()
+	bullet := "• "
+	indent := ""
+	if len(r.lists) > 0 {
+		depth := len(r.lists) - 1
+		top := &r.lists[depth]
+		indent = strings.Repeat("  ", depth)
+		if *top > 0 {
+			bullet = strconv.Itoa(*top) + ". "
+			*top++
+		}
+	}
+	// 缩进直接写出，write 会去掉行首空格
+	r.linePrefix()
+	r.emit(indent)
+	r.write(bullet)
+	r.children(n)
+	r.block()
+}
+
+// link 渲染链接：@用户显示为高亮用户名，其他链接在文字后附上地址
+func (r *renderer) link(n *html.Node) {
+	href := Attr(n, "href")
+	text := strings.TrimSpace(TextContent(n))
+
+	if _, ok := MentionName(n); ok {
+		r.styledText(text, ansiBold+ansiCyan)
+		return
+	}
+
+	// 链接内只有图片时只显示图片
+	if text == "" {
+		r.children(n)
+		return
+	}
+
+	r.push(ansiUnderline + ansiBlue)
+	r.children(n)
+	r.pop()
+	if href != "" && href != text && !strings.HasPrefix(href, "#") && !strings.HasPrefix(href, "javascript:") {
+		r.styledText(" ("+href+")", ansiDim)
+	}
+}
+
+// image 渲染图片：表情图片显示为 :名称:，普通图片显示为 [图片] 地址
+func (r *renderer) image(n *html.Node) {
+	src := Attr(n, "src")
+	alt := strings.TrimSpace(Attr(n, "alt"))
+
+	if IsEmojiImage(n.Attr) {
+		name := alt
+		if name == "" {
+			name = Attr(n, "title")
+		}
+		if name == "" {
+			name = "表情"
+		}
+		r.styledText(":"+strings.Trim(name, ":")+":", ansiYellow)
+		return
+	}
+
+	r.styledText("[图片] ", ansiMagenta)
+	r.styledText(src, ansiDim)
+}
+
+func (r *renderer) media(n *html.Node) {
+	src := Attr(n, "src")
+	if src == "" {
+		r.children(n)
+		return
+	}
+
+	label := "[视频] "
+	switch n.DataAtom {
+	case atom.Audio:
+		label = "[音频] "
+	case atom.Iframe:
+		label = "[嵌入] "
+	}
+	r.styledText(label, ansiMagenta)
+	r.styledText(src, ansiDim)
+}
+
+// IsEmojiImage 判断图片属性是否为表情图片
+func IsEmojiImage(attrs []html.Attribute) bool {
+	for _, a := range attrs {
+		switch a.Key {
+		case "class":
+			for _, c := range strings.Fields(a.Val) {
+				if c == "emoji" {
+					return true
+				}
+			}
+		case "src":
+			if strings.Contains(a.Val, "/emoji/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MentionName 判断链接节点是否为 @用户，是则返回用户名
+// 摸鱼派渲染 @用户 为指向 /member/用户名 的链接，链接文字为用户名
+func MentionName(n *html.Node) (string, bool) {
+	if n.Type != html.ElementNode || n.DataAtom != atom.A {
+		return "", false
+	}
+	href := Attr(n, "href")
+	text := strings.TrimPrefix(strings.TrimSpace(TextContent(n)), "@")
+
+	idx := strings.LastIndex(href, "/member/")
+	if idx < 0 {
+		return "", false
+	}
+	name := strings.Trim(href[idx+len("/member/"):], "/")
+	if strings.Contains(Attr(n, "class"), "name-at") || strings.EqualFold(name, text) {
+		return name, name != ""
+	}
+	return "", false
+}
+
+// Attr 返回节点的属性值，不存在时返回空字符串
+func Attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// TextContent 返回节点及其子节点中的全部文本
+func TextContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(TextContent(c))
+	}
+	return b.String()
+}
+
+func hasLeadingSpace(s string) bool {
+	return s != "" && strings.TrimLeft(s, " \t\r\n") != s
+}
+
+func hasTrailingSpace(s string) bool {
+	return s != "" && strings.TrimRight(s, " \t\r\n") != s
+}
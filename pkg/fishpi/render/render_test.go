@@ -0,0 +1,41 @@
+package render
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		html  string
+		color bool
+		want  string
+	}{
+		{"折叠空白", "<p>hello   world</p>", false, "hello world"},
+		{"段落", "<p>a</p><p>b</p>", false, "a\nb"},
+		{"换行去掉行尾空格", "line1<br>line2 <br>  line3", false, "line1\nline2\nline3"},
+		{"段落末尾空格", "<p>a  </p><p>b</p>", false, "a\nb"},
+		{"行内代码", "<code>x</code>", false, "`x`"},
+		{"代码块保留空白", "<pre><code>a  b\n  c</code></pre>", false, "a  b\n  c"},
+		{"无序列表", "<ul><li>a</li><li>b</li></ul>", false, "• a\n• b"},
+		{"有序列表", "<ol><li>a</li><li>b</li></ol>", false, "1. a\n2. b"},
+		{"嵌套列表", "<ul><li>a<ol><li>b</li></ol></li></ul>", false, "• a\n  1. b"},
+		{"引用", "<blockquote><p>q1</p><p>q2</p></blockquote>after", false, "│ q1\n│ q2\nafter"},
+		{"空引用", "<blockquote></blockquote>", false, ""},
+		{"链接", `<a href="https://x.com">site</a>`, false, "site (https://x.com)"},
+		{"@用户", `<a href="/member/alice" class="name-at">alice</a> hi`, false, "alice hi"},
+		{"表情", `<img class="emoji" alt="smile" src="/emoji/smile.png">`, false, ":smile:"},
+		{"图片", `<img src="https://i/p.png">`, false, "[图片] https://i/p.png"},
+		{"忽略脚本", "<script>alert(1)</script>ok", false, "ok"},
+		{"粗体", "<strong>bold</strong> text", true, "\033[1mbold\033[0m text"},
+		{"彩色行内代码", "<code>x</code>", true, "\033[33mx\033[0m"},
+		{"彩色引用", "<blockquote>q</blockquote>", true, "\033[0m\033[2m│ \033[0mq"},
+		{"样式后的空格保留", "<strong>a </strong>b", true, "\033[1ma \033[0mb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.html, tt.color); got != tt.want {
+				t.Errorf("Render(%q, %v) = %q, want %q", tt.html, tt.color, got, tt.want)
+			}
+		})
+	}
+}
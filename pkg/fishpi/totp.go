@@ -0,0 +1,66 @@
+package fishpi
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// TOTPPeriod TOTP 时间步长（秒）
+	TOTPPeriod = 30
+	// TOTPDigits TOTP 验证码位数
+	TOTPDigits = 6
+)
+
+// GenerateTOTP 根据 RFC 6238 生成指定时间的二重验证码
+// secret: Base32 编码的 TOTP 密钥（即绑定验证器时显示的密钥，忽略大小写和空格）
+// t: 生成验证码所使用的时间，一般传 time.Now()
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	counter := uint64(t.Unix() / TOTPPeriod)
+	return hotp(key, counter), nil
+}
+
+// decodeTOTPSecret 解码 Base32 格式的 TOTP 密钥
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	cleaned := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(secret), " ", ""))
+	cleaned = strings.TrimRight(cleaned, "=")
+	if cleaned == "" {
+		return nil, fmt.Errorf("TOTP 密钥不能为空")
+	}
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("TOTP 密钥格式错误（应为 Base32）: %w", err)
+	}
+	return key, nil
+}
+
+// hotp 按 RFC 4226 计算 HMAC-SHA1 一次性密码
+func hotp(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// 动态截断：取最后一个字节的低 4 位作为偏移量
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTPDigits, code%mod)
+}
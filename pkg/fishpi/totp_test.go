@@ -0,0 +1,69 @@
+package fishpi
+
+import (
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试向量（密钥为 ASCII "12345678901234567890"），取 8 位结果的后 6 位
+func TestGenerateTOTP(t *testing.T) {
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := GenerateTOTP(secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateTOTP(%d) 返回错误: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("GenerateTOTP(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateTOTPSecretFormat(t *testing.T) {
+	want, err := GenerateTOTP("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", time.Unix(59, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"小写和空格", "gezd gnbv gy3t qojq gezd gnbv gy3t qojq", false},
+		{"带填充", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ====", false},
+		{"空密钥", "  ", true},
+		{"非 Base32", "not-base32!", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateTOTP(tt.secret, time.Unix(59, 0))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望返回错误，得到 %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
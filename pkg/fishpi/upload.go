@@ -0,0 +1,117 @@
+package fishpi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+
+	"go.uber.org/zap"
+)
+
+// 可以在 Markdown 中直接以图片形式展示的扩展名
+var imageExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+	".bmp":  true,
+	".svg":  true,
+}
+
+// UploadFiles 上传本地文件，返回文件地址及可直接发送的 Markdown 片段
+// 部分文件上传失败时仍返回成功的文件，同时返回错误说明失败的文件。
+func (c *Client) UploadFiles(paths ...string) ([]models.UploadedFile, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("API Key未设置，请先登录")
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("请指定要上传的文件")
+	}
+
+	c.Logger.Info("上传文件", zap.Strings("paths", paths))
+
+	// 构建 multipart 请求体
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	names := make([]string, 0, len(paths))
+	for _, p := range paths {
+		name := filepath.Base(p)
+		if err := addFormFile(writer, p, name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("构建上传请求失败: %w", err)
+	}
+
+	resp, err := c.doRawRequest(http.MethodPost, "/upload", &buf, writer.FormDataContentType(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.UploadResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("上传文件失败: %s", result.Msg)
+	}
+
+	// 按上传顺序整理结果
+	files := make([]models.UploadedFile, 0, len(result.Data.SuccMap))
+	for _, name := range names {
+		url, ok := result.Data.SuccMap[name]
+		if !ok {
+			continue
+		}
+		isImage := imageExtensions[strings.ToLower(filepath.Ext(name))]
+		markdown := fmt.Sprintf("[%s](%s)", name, url)
+		if isImage {
+			markdown = "!" + markdown
+		}
+		files = append(files, models.UploadedFile{
+			Name:     name,
+			URL:      url,
+			IsImage:  isImage,
+			Markdown: markdown,
+		})
+	}
+
+	c.Logger.Info("上传文件完成",
+		zap.Int("succeeded", len(files)),
+		zap.Strings("failed", result.Data.ErrFiles),
+	)
+
+	if len(result.Data.ErrFiles) > 0 {
+		return files, fmt.Errorf("部分文件上传失败: %s", strings.Join(result.Data.ErrFiles, ", "))
+	}
+	return files, nil
+}
+
+// addFormFile 将本地文件写入 multipart 的 file[] 字段
+func addFormFile(writer *multipart.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer f.Close()
+
+	part, err := writer.CreateFormFile("file[]", name)
+	if err != nil {
+		return fmt.Errorf("构建上传请求失败: %w", err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package fishpi
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"dpbug/fishpi/go-client/pkg/fishpi/models"
 
@@ -141,3 +142,35 @@ func (c *Client) IsCollectedLiveness() (bool, error) {
 
 	return status.IsCollectedYesterdayLivenessReward, nil
 }
+
+// SearchUsernames 按前缀查询用户名（用于 @用户 补全）
+// prefix: 用户名前缀（不含 @）
+func (c *Client) SearchUsernames(prefix string) ([]models.UsernameSuggestion, error) {
+	prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "@")
+	if prefix == "" {
+		return nil, fmt.Errorf("用户名前缀不能为空")
+	}
+
+	c.Logger.Info("查询用户名", zap.String("prefix", prefix))
+
+	reqBody := map[string]interface{}{
+		"name": prefix,
+	}
+
+	resp, err := c.doRequest(http.MethodPost, "/users/names", reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.UsernameSearchResponse
+	if err := c.parseResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Code != 0 {
+		return nil, fmt.Errorf("查询用户名失败: %s", result.Msg)
+	}
+
+	c.Logger.Info("查询用户名成功", zap.Int("count", len(result.Data)))
+	return result.Data, nil
+}
@@ -0,0 +1,160 @@
+package fishpi
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dpbug/fishpi/go-client/pkg/fishpi/models"
+)
+
+// DefaultUserCacheSize 用户缓存默认容量
+const DefaultUserCacheSize = 500
+
+// CachedUser 最近出现过的用户
+type CachedUser struct {
+	UserName      string
+	UserNickname  string
+	UserAvatarURL string
+	LastSeen      time.Time
+}
+
+// UserCache 最近出现用户的内存缓存，用于 @用户名 补全
+// 超出容量时淘汰最久未出现的用户，可并发使用。
+type UserCache struct {
+	capacity int
+	users    map[string]*CachedUser // key 为小写用户名
+	mu       sync.RWMutex
+}
+
+// NewUserCache 创建用户缓存，capacity 非正值时使用 DefaultUserCacheSize
+func NewUserCache(capacity int) *UserCache {
+	if capacity <= 0 {
+		capacity = DefaultUserCacheSize
+	}
+	return &UserCache{
+		capacity: capacity,
+		users:    make(map[string]*CachedUser),
+	}
+}
+
+// Add 记录一个用户，已存在时更新昵称、头像和出现时间
+func (c *UserCache) Add(userName, nickname, avatarURL string) {
+	userName = strings.TrimSpace(userName)
+	if userName == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := strings.ToLower(userName)
+	if u, ok := c.users[key]; ok {
+		if nickname != "" {
+			u.UserNickname = nickname
+		}
+		if avatarURL != "" {
+			u.UserAvatarURL = avatarURL
+		}
+		u.LastSeen = time.Now()
+		return
+	}
+
+	if len(c.users) >= c.capacity {
+		c.evictOldest()
+	}
+	c.users[key] = &CachedUser{
+		UserName:      userName,
+		UserNickname:  nickname,
+		UserAvatarURL: avatarURL,
+		LastSeen:      time.Now(),
+	}
+}
+
+// Observe 从聊天室消息中记录发送者及红包领取者
+func (c *UserCache) Observe(msg *models.ChatMessage) {
+	if msg == nil {
+		return
+	}
+	c.Add(msg.UserName, msg.UserNickname, msg.UserAvatarURL)
+
+	if msg.IsRedPacket() {
+		if rp, err := msg.GetRedPacket(); err == nil {
+			for _, w := range rp.Who {
+				c.Add(w.UserName, "", w.Avatar)
+			}
+		}
+	}
+}
+
+// AddSuggestions 记录用户名查询接口返回的用户
+func (c *UserCache) AddSuggestions(suggestions []models.UsernameSuggestion) {
+	for _, s := range suggestions {
+		c.Add(s.UserName, "", s.UserAvatarURL)
+	}
+}
+
+// Complete 返回以 prefix 开头（忽略大小写）的用户名，按最近出现时间排序
+// limit 非正值表示不限制数量
+func (c *UserCache) Complete(prefix string, limit int) []string {
+	prefix = strings.ToLower(strings.TrimPrefix(prefix, "@"))
+
+	// 在锁内复制排序所需的字段，避免与 Observe 并发修改同一条目
+	type match struct {
+		name     string
+		lastSeen time.Time
+	}
+	c.mu.RLock()
+	matches := make([]match, 0)
+	for key, u := range c.users {
+		if strings.HasPrefix(key, prefix) {
+			matches = append(matches, match{name: u.UserName, lastSeen: u.LastSeen})
+		}
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].lastSeen.After(matches[j].lastSeen)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = m.name
+	}
+	return names
+}
+
+// Get 按用户名查询缓存（忽略大小写）
+func (c *UserCache) Get(userName string) (CachedUser, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	u, ok := c.users[strings.ToLower(userName)]
+	if !ok {
+		return CachedUser{}, false
+	}
+	return *u, true
+}
+
+// Len 返回缓存中的用户数量
+func (c *UserCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.users)
+}
+
+// evictOldest 淘汰最久未出现的用户，调用方需持有写锁
+func (c *UserCache) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for key, u := range c.users {
+		if oldestKey == "" || u.LastSeen.Before(oldest) {
+			oldestKey, oldest = key, u.LastSeen
+		}
+	}
+	delete(c.users, oldestKey)
+}
@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,59 +19,207 @@ const (
 	defaultHeartbeatPayload   = "-hb-"
 	defaultWriteTimeout       = 10 * time.Second
 	websocketHandshakeTimeout = 10 * time.Second
+	defaultPingInterval       = 30 * time.Second
+	defaultIdleTimeout        = 90 * time.Second
+	defaultSendQueueSize      = 64
 )
 
+// ErrStaleConnection 超过空闲超时仍未收到任何数据（包括 pong），连接可能已半开，
+// 调用方应关闭连接并重新连接。
+var ErrStaleConnection = errors.New("聊天室连接已失效")
+
+// ErrConnClosed 连接已关闭或写协程已因写入失败退出
+var ErrConnClosed = errors.New("聊天室连接已关闭")
+
+// 帧类型，与 gorilla/websocket 的消息类型一致
+const (
+	TextFrame   = websocket.TextMessage
+	BinaryFrame = websocket.BinaryMessage
+)
+
+// Frame 待发送的 WebSocket 数据帧
+type Frame struct {
+	Type int // TextFrame 或 BinaryFrame
+	Data []byte
+}
+
+// NewTextFrame 创建文本帧
+func NewTextFrame(text string) Frame {
+	return Frame{Type: TextFrame, Data: []byte(text)}
+}
+
+// outboundFrame 发送队列中的帧，写入结果通过 result 返回
+type outboundFrame struct {
+	frame  Frame
+	result chan error
+}
+
 // ChatRoomConn 封装聊天室 WebSocket 连接，及后台心跳。
+// 所有写操作（心跳、ping、Send）都由同一个写协程串行执行。
 type ChatRoomConn struct {
-	Conn              *websocket.Conn
+	conn              *websocket.Conn
 	logger            *zap.Logger
 	heartbeatInterval time.Duration
 	heartbeatPayload  []byte
-	stopHeartbeat     chan struct{}
-	once              sync.Once
+	pingInterval      time.Duration
+	idleTimeout       time.Duration
+	outbound          chan outboundFrame
+	closed            chan struct{} // 关闭连接或写协程退出时关闭
+	writerDone        chan struct{}
+	closeOnce         sync.Once
+	closeErr          error
 }
 
-// Close 关闭 WebSocket 连接, 停止心跳。
+// ReadMessage 读取下一条消息，每次收到数据都会顺延读超时。
+// 超过空闲超时未收到任何数据时返回包装了 ErrStaleConnection 的错误。
+// ReadMessage 不能并发调用。
+func (c *ChatRoomConn) ReadMessage() (int, []byte, error) {
+	messageType, data, err := c.conn.ReadMessage()
+	if err != nil {
+		var netErr interface{ Timeout() bool }
+		if c.idleTimeout > 0 && errors.As(err, &netErr) && netErr.Timeout() {
+			c.logger.Warn("聊天室连接空闲超时", zap.Duration("idle_timeout", c.idleTimeout))
+			return messageType, nil, fmt.Errorf("%w: %s 内未收到任何数据", ErrStaleConnection, c.idleTimeout)
+		}
+		return messageType, nil, err
+	}
+	c.extendReadDeadline()
+	return messageType, data, nil
+}
+
+// Send 将帧放入发送队列并等待写协程写出。
+// 队列已满时阻塞，直到有空位、ctx 结束或连接关闭。可与心跳并发安全调用。
+// ctx 只作用于入队：返回 ctx 的错误时帧一定没有发送；帧入队后不再响应 ctx，
+// 等待写出结果（写入本身受写超时限制）或连接关闭。
+func (c *ChatRoomConn) Send(ctx context.Context, frame Frame) error {
+	if frame.Type != TextFrame && frame.Type != BinaryFrame {
+		return fmt.Errorf("不支持的帧类型: %d", frame.Type)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	out := outboundFrame{frame: frame, result: make(chan error, 1)}
+	select {
+	case c.outbound <- out:
+	case <-c.closed:
+		return ErrConnClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-out.result:
+		return err
+	case <-c.closed:
+		// 写协程可能在退出前已处理了该帧
+		select {
+		case err := <-out.result:
+			return err
+		default:
+			return ErrConnClosed
+		}
+	}
+}
+
+// Close 停止写协程，发送关闭帧并关闭 WebSocket 连接。
 func (c *ChatRoomConn) Close() error {
-	var closeErr error
-	c.once.Do(func() {
-		close(c.stopHeartbeat)
-		closeErr = c.Conn.Close()
+	c.closeOnce.Do(func() {
+		c.shutdown()
+		<-c.writerDone
+		c.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(time.Second))
+		c.closeErr = c.conn.Close()
 	})
-	return closeErr
+	return c.closeErr
 }
 
-func (c *ChatRoomConn) startHeartbeatLoop() {
-	if c.heartbeatInterval <= 0 || len(c.heartbeatPayload) == 0 {
-		return
+// shutdown 标记连接关闭，可重复调用
+func (c *ChatRoomConn) shutdown() {
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
 	}
+}
+
+// extendReadDeadline 顺延读超时，未设置空闲超时时不做处理
+func (c *ChatRoomConn) extendReadDeadline() {
+	if c.idleTimeout > 0 {
+		c.conn.SetReadDeadline(time.Now().Add(c.idleTimeout))
+	}
+}
 
-	ticker := time.NewTicker(c.heartbeatInterval)
-	defer ticker.Stop()
+// writeLoop 唯一的写协程，串行发送队列中的帧、文本心跳和协议层 ping
+func (c *ChatRoomConn) writeLoop() {
+	defer close(c.writerDone)
+
+	var heartbeat, ping <-chan time.Time
+	if c.heartbeatInterval > 0 && len(c.heartbeatPayload) > 0 {
+		ticker := time.NewTicker(c.heartbeatInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+	if c.pingInterval > 0 {
+		ticker := time.NewTicker(c.pingInterval)
+		defer ticker.Stop()
+		ping = ticker.C
+	}
 
 	for {
 		select {
-		case <-ticker.C:
-			c.Conn.SetWriteDeadline(time.Now().Add(defaultWriteTimeout))
-			if err := c.Conn.WriteMessage(websocket.TextMessage, c.heartbeatPayload); err != nil {
+		case out := <-c.outbound:
+			err := c.write(out.frame.Type, out.frame.Data)
+			out.result <- err
+			if err != nil {
+				c.logger.Warn("聊天室消息发送失败", zap.Error(err))
+				c.shutdown()
+				return
+			}
+		case <-heartbeat:
+			if err := c.write(websocket.TextMessage, c.heartbeatPayload); err != nil {
 				c.logger.Warn("聊天室心跳发送失败", zap.Error(err))
+				c.shutdown()
+				return
+			}
+		case <-ping:
+			// 协议层 ping，对端回复的 pong 会顺延读超时
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(defaultWriteTimeout)); err != nil {
+				c.logger.Warn("聊天室 ping 发送失败", zap.Error(err))
+				c.shutdown()
 				return
 			}
-		case <-c.stopHeartbeat:
+		case <-c.closed:
 			return
 		}
 	}
 }
 
+// write 写出一帧，仅由写协程调用
+func (c *ChatRoomConn) write(messageType int, data []byte) error {
+	c.conn.SetWriteDeadline(time.Now().Add(defaultWriteTimeout))
+	return c.conn.WriteMessage(messageType, data)
+}
+
 type chatRoomConnConfig struct {
 	heartbeatInterval time.Duration
 	heartbeatPayload  string
+	pingInterval      time.Duration
+	idleTimeout       time.Duration
+	sendQueueSize     int
+	nodeStrategy      int
+	nodeName          string
 }
 
 func defaultChatRoomConnConfig() chatRoomConnConfig {
 	return chatRoomConnConfig{
 		heartbeatInterval: defaultHeartbeatInterval,
 		heartbeatPayload:  defaultHeartbeatPayload,
+		pingInterval:      defaultPingInterval,
+		idleTimeout:       defaultIdleTimeout,
+		sendQueueSize:     defaultSendQueueSize,
 	}
 }
 
@@ -92,6 +241,53 @@ func WithChatRoomHeartbeatPayload(payload string) ChatRoomConnOption {
 	}
 }
 
+// 覆盖发送协议层 ping 的时间间隔。
+// 提供非正值将禁用 ping。
+func WithChatRoomPingInterval(interval time.Duration) ChatRoomConnOption {
+	return func(cfg *chatRoomConnConfig) {
+		cfg.pingInterval = interval
+	}
+}
+
+// 覆盖空闲超时，超过该时间未收到任何数据（消息或 pong）时 ReadMessage 返回 ErrStaleConnection。
+// 空闲超时应大于 ping 间隔；提供非正值将禁用读超时。
+func WithChatRoomIdleTimeout(timeout time.Duration) ChatRoomConnOption {
+	return func(cfg *chatRoomConnConfig) {
+		cfg.idleTimeout = timeout
+	}
+}
+
+// 覆盖发送队列的容量，队列满时 Send 会阻塞。
+func WithChatRoomSendQueueSize(size int) ChatRoomConnOption {
+	return func(cfg *chatRoomConnConfig) {
+		if size > 0 {
+			cfg.sendQueueSize = size
+		}
+	}
+}
+
+// 连接指定名称（或地址）的节点，仅对 ConnectChatRoomNode 生效。
+func WithChatRoomNode(name string) ChatRoomConnOption {
+	return func(cfg *chatRoomConnConfig) {
+		cfg.nodeStrategy = NodeStrategyByName
+		cfg.nodeName = name
+	}
+}
+
+// 探测所有节点，连接握手延迟最低的节点，仅对 ConnectChatRoomNode 生效。
+func WithChatRoomFastestNode() ChatRoomConnOption {
+	return func(cfg *chatRoomConnConfig) {
+		cfg.nodeStrategy = NodeStrategyFastest
+	}
+}
+
+// 连接在线人数最少的节点，仅对 ConnectChatRoomNode 生效。
+func WithChatRoomLeastLoadedNode() ChatRoomConnOption {
+	return func(cfg *chatRoomConnConfig) {
+		cfg.nodeStrategy = NodeStrategyLeastLoaded
+	}
+}
+
 // 打开聊天室频道的 WebSocket 连接。
 // 提供的 context 控制拨号超时；记得在返回的连接上调用 Close。
 // wsURL 是从 /chat-room/node/get 接口返回的完整 WebSocket 地址（已包含 apiKey 参数）。
@@ -132,13 +328,29 @@ func ConnectChatRoom(ctx context.Context, wsURL, userAgent string, logger *zap.L
 	wsConn.SetWriteDeadline(time.Time{})
 
 	chatConn := &ChatRoomConn{
-		Conn:              wsConn,
+		conn:              wsConn,
 		logger:            logger,
 		heartbeatInterval: cfg.heartbeatInterval,
 		heartbeatPayload:  []byte(cfg.heartbeatPayload),
-		stopHeartbeat:     make(chan struct{}),
+		pingInterval:      cfg.pingInterval,
+		idleTimeout:       cfg.idleTimeout,
+		outbound:          make(chan outboundFrame, cfg.sendQueueSize),
+		closed:            make(chan struct{}),
+		writerDone:        make(chan struct{}),
 	}
 
+	// 收到 pong 或 ping 都说明连接仍然存活，顺延读超时
+	chatConn.extendReadDeadline()
+	wsConn.SetPongHandler(func(string) error {
+		chatConn.extendReadDeadline()
+		return nil
+	})
+	defaultPingHandler := wsConn.PingHandler()
+	wsConn.SetPingHandler(func(appData string) error {
+		chatConn.extendReadDeadline()
+		return defaultPingHandler(appData)
+	})
+
 	defaultCloseHandler := wsConn.CloseHandler()
 	wsConn.SetCloseHandler(func(code int, text string) error {
 		logger.Info("聊天室连接关闭", zap.Int("code", code), zap.String("text", text))
@@ -148,9 +360,7 @@ func ConnectChatRoom(ctx context.Context, wsURL, userAgent string, logger *zap.L
 		return nil
 	})
 
-	if cfg.heartbeatInterval > 0 && cfg.heartbeatPayload != "" {
-		go chatConn.startHeartbeatLoop()
-	}
+	go chatConn.writeLoop()
 
 	logger.Info("聊天室 WebSocket 连接成功")
 	return chatConn, nil
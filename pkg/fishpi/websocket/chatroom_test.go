@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// newEchoServer 启动测试服务，将收到的每一帧写入 received
+func newEchoServer(t *testing.T, received chan<- string) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			received <- string(data)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestChatRoomConnSend(t *testing.T) {
+	received := make(chan string, 8)
+	conn, err := ConnectChatRoom(context.Background(), newEchoServer(t, received), "", zap.NewNop(),
+		WithChatRoomHeartbeatInterval(0), WithChatRoomPingInterval(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ctx 已结束时帧一定不会发送
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := conn.Send(cancelled, NewTextFrame("cancelled")); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Send with cancelled ctx = %v, want context.Canceled", err)
+	}
+
+	if err := conn.Send(context.Background(), NewTextFrame("hello")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	select {
+	case got := <-received:
+		if got != "hello" {
+			t.Errorf("server received %q, want hello", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not receive frame")
+	}
+
+	if err := conn.Send(context.Background(), Frame{Type: websocket.PingMessage}); err == nil {
+		t.Error("Send with control frame type should fail")
+	}
+
+	conn.Close()
+	if err := conn.Send(context.Background(), NewTextFrame("closed")); !errors.Is(err, ErrConnClosed) {
+		t.Errorf("Send after Close = %v, want ErrConnClosed", err)
+	}
+}
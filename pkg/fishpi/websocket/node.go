@@ -0,0 +1,164 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// 节点选择策略
+const (
+	NodeStrategyAuto        = iota // 使用服务端自动分配的节点
+	NodeStrategyByName             // 按名称选择节点
+	NodeStrategyFastest            // 选择握手延迟最低的节点
+	NodeStrategyLeastLoaded        // 选择在线人数最少的节点
+)
+
+// Node 聊天室节点
+type Node struct {
+	Name   string // 节点中文名称
+	URL    string // 节点 WSS 地址（不含 apiKey）
+	Online int    // 当前在线人数
+	Weight int    // 节点权重
+}
+
+// NodeList 从 /chat-room/node/get 获取到的节点信息
+type NodeList struct {
+	DefaultURL string // 服务端自动分配的完整地址（已包含 apiKey）
+	APIKey     string // 手动选择节点时与节点地址拼接的 apiKey
+	Nodes      []Node
+}
+
+// NodeProbe 节点探测结果
+type NodeProbe struct {
+	Node
+	Latency time.Duration // WebSocket 握手耗时
+	Err     error         // 探测失败原因
+}
+
+// NodeURL 拼接节点地址和 apiKey
+func NodeURL(nodeURL, apiKey string) string {
+	sep := "?"
+	if strings.Contains(nodeURL, "?") {
+		sep = "&"
+	}
+	return nodeURL + sep + url.Values{"apiKey": {apiKey}}.Encode()
+}
+
+// ProbeNodes 并发探测所有节点的握手延迟，结果按延迟从低到高排序（失败的节点排在最后）
+func ProbeNodes(ctx context.Context, list NodeList, userAgent string) []NodeProbe {
+	probes := make([]NodeProbe, len(list.Nodes))
+
+	var wg sync.WaitGroup
+	for i, node := range list.Nodes {
+		wg.Add(1)
+		go func(i int, node Node) {
+			defer wg.Done()
+			latency, err := probeNode(ctx, NodeURL(node.URL, list.APIKey), userAgent)
+			probes[i] = NodeProbe{Node: node, Latency: latency, Err: err}
+		}(i, node)
+	}
+	wg.Wait()
+
+	sort.SliceStable(probes, func(i, j int) bool {
+		if (probes[i].Err == nil) != (probes[j].Err == nil) {
+			return probes[i].Err == nil
+		}
+		return probes[i].Latency < probes[j].Latency
+	})
+	return probes
+}
+
+// probeNode 建立一次 WebSocket 握手并立即关闭，返回握手耗时
+func probeNode(ctx context.Context, wsURL, userAgent string) (time.Duration, error) {
+	header := http.Header{}
+	if ua := strings.TrimSpace(userAgent); ua != "" {
+		header.Set("User-Agent", ua)
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = websocketHandshakeTimeout
+
+	start := time.Now()
+	conn, resp, err := dialer.DialContext(ctx, wsURL, header)
+	latency := time.Since(start)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second))
+	conn.Close()
+	return latency, nil
+}
+
+// selectNodeURL 根据节点选择策略确定要连接的地址
+func selectNodeURL(ctx context.Context, list NodeList, userAgent string, cfg chatRoomConnConfig, logger *zap.Logger) (string, error) {
+	switch cfg.nodeStrategy {
+	case NodeStrategyByName:
+		for _, node := range list.Nodes {
+			if node.Name == cfg.nodeName || node.URL == cfg.nodeName {
+				return NodeURL(node.URL, list.APIKey), nil
+			}
+		}
+		return "", fmt.Errorf("未找到聊天室节点: %s", cfg.nodeName)
+
+	case NodeStrategyFastest:
+		probes := ProbeNodes(ctx, list, userAgent)
+		if len(probes) == 0 || probes[0].Err != nil {
+			logger.Warn("聊天室节点探测全部失败，使用自动分配的节点")
+			return list.DefaultURL, nil
+		}
+		logger.Info("选择延迟最低的聊天室节点",
+			zap.String("name", probes[0].Name),
+			zap.Duration("latency", probes[0].Latency),
+		)
+		return NodeURL(probes[0].URL, list.APIKey), nil
+
+	case NodeStrategyLeastLoaded:
+		if len(list.Nodes) == 0 {
+			return list.DefaultURL, nil
+		}
+		best := list.Nodes[0]
+		for _, node := range list.Nodes[1:] {
+			if node.Online < best.Online {
+				best = node
+			}
+		}
+		logger.Info("选择在线人数最少的聊天室节点",
+			zap.String("name", best.Name),
+			zap.Int("online", best.Online),
+		)
+		return NodeURL(best.URL, list.APIKey), nil
+
+	default:
+		return list.DefaultURL, nil
+	}
+}
+
+// ConnectChatRoomNode 按节点选择策略连接聊天室，策略通过 WithChatRoomNode 等选项指定，
+// 默认使用服务端自动分配的节点。
+func ConnectChatRoomNode(ctx context.Context, list NodeList, userAgent string, logger *zap.Logger, opts ...ChatRoomConnOption) (*ChatRoomConn, error) {
+	cfg := defaultChatRoomConnConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	wsURL, err := selectNodeURL(ctx, list, userAgent, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return ConnectChatRoom(ctx, wsURL, userAgent, logger, opts...)
+}
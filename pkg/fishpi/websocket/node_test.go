@@ -0,0 +1,19 @@
+package websocket
+
+import "testing"
+
+func TestNodeURL(t *testing.T) {
+	tests := []struct {
+		node, apiKey, want string
+	}{
+		{"wss://fishpi.cn/chat-room-channel", "abc", "wss://fishpi.cn/chat-room-channel?apiKey=abc"},
+		{"wss://fishpi.cn/chat-room-channel?x=1", "abc", "wss://fishpi.cn/chat-room-channel?x=1&apiKey=abc"},
+		{"wss://fishpi.cn/chat-room-channel", "a+b/c=&d", "wss://fishpi.cn/chat-room-channel?apiKey=a%2Bb%2Fc%3D%26d"},
+	}
+
+	for _, tt := range tests {
+		if got := NodeURL(tt.node, tt.apiKey); got != tt.want {
+			t.Errorf("NodeURL(%q, %q) = %q, want %q", tt.node, tt.apiKey, got, tt.want)
+		}
+	}
+}